@@ -0,0 +1,96 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroupfs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CPUStat holds the CPU usage and throttling statistics of a cgroup.
+type CPUStat struct {
+	// Total CPU time consumed by the tasks of the cgroup.
+	Usage time.Duration
+	// CPU time consumed in user and system mode.
+	User   time.Duration
+	System time.Duration
+	// Number of enforcement periods that have elapsed.
+	NrPeriods uint64
+	// Number of times the cgroup has been throttled because it exhausted
+	// its quota within a period.
+	NrThrottled uint64
+	// Total time the tasks of the cgroup have been throttled.
+	Throttled time.Duration
+}
+
+// CPUMax holds the CPU bandwidth limit of a cgroup: the tasks may run for
+// Quota in each Period.
+type CPUMax struct {
+	// The quota, or nil if the bandwidth is not limited.
+	Quota  *time.Duration
+	Period time.Duration
+}
+
+// CPUStat returns the CPU usage of the cgroup, read from cpu.stat. The
+// throttling statistics are only available if the cpu controller is
+// enabled for the cgroup.
+func (fs FS) CPUStat(cgroup string) (CPUStat, error) {
+	kvs, err := readKeyValues(fs.Path(cgroup, "cpu.stat"))
+	if err != nil {
+		return CPUStat{}, err
+	}
+
+	return CPUStat{
+		Usage:       time.Duration(kvs["usage_usec"]) * time.Microsecond,
+		User:        time.Duration(kvs["user_usec"]) * time.Microsecond,
+		System:      time.Duration(kvs["system_usec"]) * time.Microsecond,
+		NrPeriods:   kvs["nr_periods"],
+		NrThrottled: kvs["nr_throttled"],
+		Throttled:   time.Duration(kvs["throttled_usec"]) * time.Microsecond,
+	}, nil
+}
+
+// CPUMax returns the CPU bandwidth limit of the cgroup, read from cpu.max.
+func (fs FS) CPUMax(cgroup string) (CPUMax, error) {
+	path := fs.Path(cgroup, "cpu.max")
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return CPUMax{}, err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 {
+		return CPUMax{}, fmt.Errorf("couldn't parse %s: unexpected format %q", path, data)
+	}
+
+	quota, err := parseLimit(fields[0])
+	if err != nil {
+		return CPUMax{}, fmt.Errorf("couldn't parse %s: %s", path, err)
+	}
+	period, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return CPUMax{}, fmt.Errorf("couldn't parse %s: invalid period: %s", path, err)
+	}
+
+	m := CPUMax{Period: time.Duration(period) * time.Microsecond}
+	if quota != nil {
+		q := time.Duration(*quota) * time.Microsecond
+		m.Quota = &q
+	}
+
+	return m, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroupfs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCPUStat(t *testing.T) {
+	for _, tt := range []struct {
+		cgroup string
+		want   CPUStat
+	}{
+		{
+			// The root cgroup has no throttling statistics.
+			cgroup: "/",
+			want: CPUStat{
+				Usage:  94717356 * time.Millisecond,
+				User:   56870513 * time.Millisecond,
+				System: 37846843 * time.Millisecond,
+			},
+		},
+		{
+			cgroup: "/system.slice",
+			want: CPUStat{
+				Usage:       4813472 * time.Millisecond,
+				User:        3310871 * time.Millisecond,
+				System:      1502601 * time.Millisecond,
+				NrPeriods:   1200,
+				NrThrottled: 31,
+				Throttled:   2351200 * time.Microsecond,
+			},
+		},
+	} {
+		have, err := unified.CPUStat(tt.cgroup)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.want != have {
+			t.Errorf("%s: want %+v, have %+v", tt.cgroup, tt.want, have)
+		}
+	}
+}
+
+func TestCPUMax(t *testing.T) {
+	m, err := unified.CPUMax("/system.slice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Quota == nil || *m.Quota != 200*time.Millisecond {
+		t.Errorf("want quota %s, have %v", 200*time.Millisecond, m.Quota)
+	}
+	if want, have := 100*time.Millisecond, m.Period; want != have {
+		t.Errorf("want period %s, have %s", want, have)
+	}
+
+	m, err = unified.CPUMax("/system.slice/docker-abc.scope")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Quota != nil {
+		t.Errorf("want no quota, have %s", *m.Quota)
+	}
+	if want, have := 100*time.Millisecond, m.Period; want != have {
+		t.Errorf("want period %s, have %s", want, have)
+	}
+}
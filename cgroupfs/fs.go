@@ -0,0 +1,87 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroupfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// FS represents the cgroup v2 unified hierarchy, which provides an
+// interface to the resource usage and limits of control groups.
+//
+// Cgroups are identified by their path relative to the root of the
+// hierarchy, as found in /proc/[pid]/cgroup, e.g.
+// "/system.slice/docker.service".
+type FS string
+
+// DefaultMountPoint is the common mount point of the cgroup filesystem.
+const DefaultMountPoint = "/sys/fs/cgroup"
+
+// NewFS returns a new FS mounted under the given mountPoint. It will error
+// if the mount point can't be read.
+func NewFS(mountPoint string) (FS, error) {
+	info, err := os.Stat(mountPoint)
+	if err != nil {
+		return "", fmt.Errorf("could not read %s: %s", mountPoint, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("mount point %s is not a directory", mountPoint)
+	}
+
+	return FS(mountPoint), nil
+}
+
+// Path returns the path of the given file or cgroup relative to the cgroup
+// root.
+func (fs FS) Path(p ...string) string {
+	return filepath.Join(append([]string{string(fs)}, p...)...)
+}
+
+// Descendants returns the paths of all cgroups below the given cgroup,
+// recursively, in lexical order. The given cgroup itself is not included.
+func (fs FS) Descendants(cgroup string) ([]string, error) {
+	return descendants(fs.Path(cgroup), cgroup)
+}
+
+func descendants(dir, cgroup string) ([]string, error) {
+	cgroups := []string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// A cgroup may be removed while walking the hierarchy.
+			if os.IsNotExist(err) && path != dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !info.IsDir() || path == dir {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		cgroups = append(cgroups, filepath.Join("/", cgroup, rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(cgroups)
+	return cgroups, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroupfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+const unified = FS("fixtures/unified")
+
+func TestNewFS(t *testing.T) {
+	if _, err := NewFS("foobar"); err == nil {
+		t.Error("want NewFS to fail for non-existing mount point")
+	}
+
+	if _, err := NewFS("doc.go"); err == nil {
+		t.Error("want NewFS to fail if mount point is not a directory")
+	}
+}
+
+func TestDescendants(t *testing.T) {
+	for _, tt := range []struct {
+		cgroup string
+		want   []string
+	}{
+		{
+			cgroup: "/",
+			want: []string{
+				"/system.slice",
+				"/system.slice/docker-abc.scope",
+				"/system.slice/sshd.service",
+				"/user.slice",
+				"/user.slice/user-1000.slice",
+			},
+		},
+		{
+			cgroup: "/system.slice",
+			want:   []string{"/system.slice/docker-abc.scope", "/system.slice/sshd.service"},
+		},
+		{
+			cgroup: "/system.slice/sshd.service",
+			want:   []string{},
+		},
+	} {
+		have, err := unified.Descendants(tt.cgroup)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tt.want, have) {
+			t.Errorf("%s: want descendants %v, have %v", tt.cgroup, tt.want, have)
+		}
+	}
+
+	if _, err := unified.Descendants("/nonexistent"); err == nil {
+		t.Error("want error for non-existing cgroup, have none")
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroupfs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Device identifies a block device by its major and minor number.
+type Device struct {
+	Major uint32
+	Minor uint32
+}
+
+func (d Device) String() string {
+	return fmt.Sprintf("%d:%d", d.Major, d.Minor)
+}
+
+// IODeviceStat holds the IO statistics of a cgroup for a single device.
+type IODeviceStat struct {
+	// Bytes read and written.
+	ReadBytes  uint64
+	WriteBytes uint64
+	// Number of read and write IOs.
+	ReadIOs  uint64
+	WriteIOs uint64
+	// Bytes and number of IOs discarded. Available since Linux 5.0.
+	DiscardBytes uint64
+	DiscardIOs   uint64
+}
+
+// IOStat holds the IO statistics of a cgroup keyed by device.
+type IOStat map[Device]IODeviceStat
+
+// IOStat returns the IO statistics of the cgroup, read from io.stat.
+func (fs FS) IOStat(cgroup string) (IOStat, error) {
+	path := fs.Path(cgroup, "io.stat")
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stat := IOStat{}
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		// Lines look like "8:16 rbytes=1459200 wbytes=314773504 rios=192
+		// wios=353 dbytes=0 dios=0".
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		dev, err := parseDevice(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: %s", path, err)
+		}
+
+		var ds IODeviceStat
+		for _, field := range fields[1:] {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("couldn't parse %s: malformed value %q", path, field)
+			}
+			var v *uint64
+			switch kv[0] {
+			case "rbytes":
+				v = &ds.ReadBytes
+			case "wbytes":
+				v = &ds.WriteBytes
+			case "rios":
+				v = &ds.ReadIOs
+			case "wios":
+				v = &ds.WriteIOs
+			case "dbytes":
+				v = &ds.DiscardBytes
+			case "dios":
+				v = &ds.DiscardIOs
+			default:
+				// Other keys, e.g. the non-integer cost.vrate of the iocost
+				// controller, are skipped.
+				continue
+			}
+
+			if *v, err = strconv.ParseUint(kv[1], 10, 64); err != nil {
+				return nil, fmt.Errorf("couldn't parse %s: invalid value for %s: %s", path, kv[0], err)
+			}
+		}
+		stat[dev] = ds
+	}
+
+	return stat, s.Err()
+}
+
+// parseDevice parses a device in the "major:minor" format.
+func parseDevice(s string) (Device, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return Device{}, fmt.Errorf("invalid device %q", s)
+	}
+
+	major, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return Device{}, fmt.Errorf("invalid device major %q: %s", parts[0], err)
+	}
+	minor, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return Device{}, fmt.Errorf("invalid device minor %q: %s", parts[1], err)
+	}
+
+	return Device{Major: uint32(major), Minor: uint32(minor)}, nil
+}
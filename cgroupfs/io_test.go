@@ -0,0 +1,60 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroupfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIOStat(t *testing.T) {
+	have, err := unified.IOStat("/system.slice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := IOStat{
+		{Major: 8, Minor: 0}: {
+			ReadBytes:  1459200,
+			WriteBytes: 314773504,
+			ReadIOs:    192,
+			WriteIOs:   353,
+		},
+		{Major: 253, Minor: 1}: {
+			ReadBytes:  90112,
+			WriteBytes: 4096,
+			ReadIOs:    22,
+			WriteIOs:   1,
+		},
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("want %+v, have %+v", want, have)
+	}
+}
+
+func TestParseDevice(t *testing.T) {
+	d, err := parseDevice("253:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := "253:1", d.String(); want != have {
+		t.Errorf("want device %s, have %s", want, have)
+	}
+
+	for _, s := range []string{"253", "a:1", "253:b", "1:2:3"} {
+		if _, err := parseDevice(s); err == nil {
+			t.Errorf("want error parsing %q, have none", s)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroupfs
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/procfs"
+)
+
+// LegacyFS represents the cgroup v1 hierarchies, which are mounted as one
+// directory per controller below a common root, e.g. /sys/fs/cgroup/memory.
+//
+// The readers of LegacyFS return the same types as the readers of FS where
+// the semantics of the values match, so that callers can handle both cgroup
+// versions alike.
+type LegacyFS struct {
+	root FS
+	// proc is the proc filesystem of the system the cgroups belong to. Its
+	// USER_HZ is the unit of the times in cpuacct.stat.
+	proc procfs.FS
+}
+
+// unlimited is the value reported by the memory controller if no limit is
+// set. It is PAGE_COUNTER_MAX in bytes, rounded down to the page size, which
+// depends on the architecture, e.g. 0x7FFFFFFFFFFFF000 with 4K pages and
+// 0x7FFFFFFFFFFF0000 with 64K pages. Any value at or above the value of the
+// largest page size is treated as unlimited.
+const unlimited = math.MaxInt64 &^ (1<<16 - 1)
+
+// NewLegacyFS returns a new LegacyFS with the controllers mounted under the
+// given mountPoint. The clock ticks of cpuacct.stat are converted using the
+// USER_HZ of the given proc filesystem. It will error if the mount point
+// can't be read.
+func NewLegacyFS(mountPoint string, proc procfs.FS) (LegacyFS, error) {
+	fs, err := NewFS(mountPoint)
+	if err != nil {
+		return LegacyFS{}, err
+	}
+
+	return LegacyFS{root: fs, proc: proc}, nil
+}
+
+// Path returns the path of the given file or cgroup of the given controller
+// relative to the cgroup root.
+func (fs LegacyFS) Path(controller string, p ...string) string {
+	return fs.root.Path(append([]string{controller}, p...)...)
+}
+
+// MemoryOOMControl holds the OOM state of a cgroup.
+type MemoryOOMControl struct {
+	// Whether the OOM killer is disabled for the cgroup.
+	OOMKillDisable bool
+	// Whether the cgroup is currently out of memory.
+	UnderOOM bool
+	// Number of processes killed by the OOM killer. Available since Linux
+	// 4.13.
+	OOMKill uint64
+}
+
+// MemoryCurrent returns the memory usage of the cgroup and its descendants
+// in bytes, read from memory.usage_in_bytes.
+func (fs LegacyFS) MemoryCurrent(cgroup string) (uint64, error) {
+	return readUint(fs.Path("memory", cgroup, "memory.usage_in_bytes"))
+}
+
+// MemoryMax returns the memory usage limit of the cgroup in bytes, read from
+// memory.limit_in_bytes. It returns nil if the usage is not limited.
+func (fs LegacyFS) MemoryMax(cgroup string) (*uint64, error) {
+	v, err := readUint(fs.Path("memory", cgroup, "memory.limit_in_bytes"))
+	if err != nil {
+		return nil, err
+	}
+	if v >= unlimited {
+		return nil, nil
+	}
+
+	return &v, nil
+}
+
+// MemoryFailcnt returns the number of times the memory usage of the cgroup
+// hit its limit, read from memory.failcnt.
+func (fs LegacyFS) MemoryFailcnt(cgroup string) (uint64, error) {
+	return readUint(fs.Path("memory", cgroup, "memory.failcnt"))
+}
+
+// MemoryStat returns the memory usage breakdown of the cgroup, read from
+// memory.stat. Like in cgroup v2, the statistics include the descendants of
+// the cgroup, and are thus taken from the hierarchical total_ entries, which
+// are mapped onto the v2 names: total_rss to Anon, total_cache to File and
+// total_mapped_file to FileMapped. KernelStack, Slab and Sock are not
+// reported by cgroup v1 and always zero.
+func (fs LegacyFS) MemoryStat(cgroup string) (MemoryStat, error) {
+	kvs, err := readKeyValues(fs.Path("memory", cgroup, "memory.stat"))
+	if err != nil {
+		return MemoryStat{}, err
+	}
+
+	return MemoryStat{
+		Anon:          kvs["total_rss"],
+		File:          kvs["total_cache"],
+		Shmem:         kvs["total_shmem"],
+		FileMapped:    kvs["total_mapped_file"],
+		FileDirty:     kvs["total_dirty"],
+		FileWriteback: kvs["total_writeback"],
+		PgFault:       kvs["total_pgfault"],
+		PgMajFault:    kvs["total_pgmajfault"],
+		Raw:           kvs,
+	}, nil
+}
+
+// MemoryOOMControl returns the OOM state of the cgroup, read from
+// memory.oom_control.
+func (fs LegacyFS) MemoryOOMControl(cgroup string) (MemoryOOMControl, error) {
+	kvs, err := readKeyValues(fs.Path("memory", cgroup, "memory.oom_control"))
+	if err != nil {
+		return MemoryOOMControl{}, err
+	}
+
+	return MemoryOOMControl{
+		OOMKillDisable: kvs["oom_kill_disable"] == 1,
+		UnderOOM:       kvs["under_oom"] == 1,
+		OOMKill:        kvs["oom_kill"],
+	}, nil
+}
+
+// CPUStat returns the CPU usage of the cgroup, read from cpuacct.usage and
+// cpuacct.stat, and its throttling statistics, read from cpu.stat. The clock
+// ticks of cpuacct.stat are converted using the USER_HZ of the proc
+// filesystem the LegacyFS was created with.
+//
+// User and System are only accurate to the clock tick, and may not add up to
+// Usage exactly.
+func (fs LegacyFS) CPUStat(cgroup string) (CPUStat, error) {
+	usage, err := readUint(fs.Path("cpuacct", cgroup, "cpuacct.usage"))
+	if err != nil {
+		return CPUStat{}, err
+	}
+	acct, err := readKeyValues(fs.Path("cpuacct", cgroup, "cpuacct.stat"))
+	if err != nil {
+		return CPUStat{}, err
+	}
+	throttling, err := readKeyValues(fs.Path("cpu", cgroup, "cpu.stat"))
+	if err != nil {
+		return CPUStat{}, err
+	}
+
+	userHZ := time.Duration(fs.proc.UserHZ())
+	return CPUStat{
+		Usage:       time.Duration(usage),
+		User:        time.Duration(acct["user"]) * time.Second / userHZ,
+		System:      time.Duration(acct["system"]) * time.Second / userHZ,
+		NrPeriods:   throttling["nr_periods"],
+		NrThrottled: throttling["nr_throttled"],
+		Throttled:   time.Duration(throttling["throttled_time"]),
+	}, nil
+}
+
+// CPUUsagePerCPU returns the CPU time consumed by the tasks of the cgroup on
+// each CPU, read from cpuacct.usage_percpu.
+func (fs LegacyFS) CPUUsagePerCPU(cgroup string) ([]time.Duration, error) {
+	path := fs.Path("cpuacct", cgroup, "cpuacct.usage_percpu")
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Fields(string(data))
+	usage := make([]time.Duration, 0, len(fields))
+	for _, f := range fields {
+		v, err := strconv.ParseUint(f, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: %s", path, err)
+		}
+		usage = append(usage, time.Duration(v))
+	}
+
+	return usage, nil
+}
+
+// CPUMax returns the CPU bandwidth limit of the cgroup, read from
+// cpu.cfs_quota_us and cpu.cfs_period_us.
+func (fs LegacyFS) CPUMax(cgroup string) (CPUMax, error) {
+	path := fs.Path("cpu", cgroup, "cpu.cfs_quota_us")
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return CPUMax{}, err
+	}
+	quota, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return CPUMax{}, fmt.Errorf("couldn't parse %s: %s", path, err)
+	}
+
+	period, err := readUint(fs.Path("cpu", cgroup, "cpu.cfs_period_us"))
+	if err != nil {
+		return CPUMax{}, err
+	}
+
+	m := CPUMax{Period: time.Duration(period) * time.Microsecond}
+	// A negative quota, usually -1, means the bandwidth is not limited.
+	if quota >= 0 {
+		q := time.Duration(quota) * time.Microsecond
+		m.Quota = &q
+	}
+
+	return m, nil
+}
+
+// IOStat returns the IO statistics of the cgroup, read from
+// blkio.throttle.io_service_bytes and blkio.throttle.io_serviced. These are
+// accounted by the throttling policy, and thus available regardless of the
+// IO scheduler in use.
+func (fs LegacyFS) IOStat(cgroup string) (IOStat, error) {
+	bytes, err := readBlkioStat(fs.Path("blkio", cgroup, "blkio.throttle.io_service_bytes"))
+	if err != nil {
+		return nil, err
+	}
+	ios, err := readBlkioStat(fs.Path("blkio", cgroup, "blkio.throttle.io_serviced"))
+	if err != nil {
+		return nil, err
+	}
+
+	stat := IOStat{}
+	for dev, ops := range bytes {
+		ds := stat[dev]
+		ds.ReadBytes, ds.WriteBytes, ds.DiscardBytes = ops["Read"], ops["Write"], ops["Discard"]
+		stat[dev] = ds
+	}
+	for dev, ops := range ios {
+		ds := stat[dev]
+		ds.ReadIOs, ds.WriteIOs, ds.DiscardIOs = ops["Read"], ops["Write"], ops["Discard"]
+		stat[dev] = ds
+	}
+
+	return stat, nil
+}
+
+// readBlkioStat reads a blkio statistics file, which holds a value per
+// device and operation:
+//
+//	8:0 Read 1459200
+//	8:0 Write 314773504
+//	8:0 Sync 314773504
+//	8:0 Async 1459200
+//	8:0 Total 316232704
+//	Total 316232704
+func readBlkioStat(path string) (map[Device]map[string]uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := map[Device]map[string]uint64{}
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || fields[0] == "Total" {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("couldn't parse %s: malformed line %q", path, s.Text())
+		}
+
+		dev, err := parseDevice(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: %s", path, err)
+		}
+		v, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: invalid value for %s: %s", path, fields[1], err)
+		}
+
+		if values[dev] == nil {
+			values[dev] = map[string]uint64{}
+		}
+		values[dev][fields[1]] = v
+	}
+
+	return values, s.Err()
+}
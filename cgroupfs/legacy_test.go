@@ -0,0 +1,208 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroupfs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prometheus/procfs"
+)
+
+// Without an auxv in the proc fixtures, USER_HZ defaults to 100.
+var legacy = LegacyFS{root: "fixtures/legacy", proc: procfs.FS("fixtures/legacy/proc")}
+
+func TestNewLegacyFS(t *testing.T) {
+	if _, err := NewLegacyFS("foobar", procfs.FS(procfs.DefaultMountPoint)); err == nil {
+		t.Error("want NewLegacyFS to fail for non-existing mount point")
+	}
+}
+
+func TestLegacyMemory(t *testing.T) {
+	current, err := legacy.MemoryCurrent("/docker/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := uint64(104857600); want != current {
+		t.Errorf("want memory current %d, have %d", want, current)
+	}
+
+	max, err := legacy.MemoryMax("/docker/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if max == nil || *max != 268435456 {
+		t.Errorf("want memory max %d, have %v", 268435456, max)
+	}
+
+	max, err = legacy.MemoryMax("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if max != nil {
+		t.Errorf("want no memory max, have %d", *max)
+	}
+
+	// Unlimited on a kernel with 64K pages.
+	max, err = legacy.MemoryMax("/docker/def")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if max != nil {
+		t.Errorf("want no memory max with 64K pages, have %d", *max)
+	}
+
+	failcnt, err := legacy.MemoryFailcnt("/docker/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := uint64(17); want != failcnt {
+		t.Errorf("want failcnt %d, have %d", want, failcnt)
+	}
+
+	oom, err := legacy.MemoryOOMControl("/docker/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (MemoryOOMControl{UnderOOM: true, OOMKill: 2}); want != oom {
+		t.Errorf("want %+v, have %+v", want, oom)
+	}
+}
+
+func TestLegacyMemoryStat(t *testing.T) {
+	s, err := legacy.MemoryStat("/docker/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := legacy.MemoryStat("/docker/abc/child")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		want uint64
+		have uint64
+	}{
+		{name: "anon", want: 71303168, have: s.Anon},
+		{name: "file", want: 46137344, have: s.File},
+		{name: "shmem", want: 4096, have: s.Shmem},
+		{name: "file_mapped", want: 9437184, have: s.FileMapped},
+		{name: "file_dirty", want: 16384, have: s.FileDirty},
+		{name: "file_writeback", want: 8192, have: s.FileWriteback},
+		{name: "pgfault", want: 100000, have: s.PgFault},
+		{name: "pgmajfault", want: 40, have: s.PgMajFault},
+		{name: "rss_huge", want: 20971520, have: s.Raw["rss_huge"]},
+		{name: "child anon", want: 8388608, have: child.Anon},
+		{name: "child file", want: 4194304, have: child.File},
+		{name: "child pgfault", want: 9000, have: child.PgFault},
+	} {
+		if tt.want != tt.have {
+			t.Errorf("want %s %d, have %d", tt.name, tt.want, tt.have)
+		}
+	}
+}
+
+func TestLegacyCPU(t *testing.T) {
+	s, err := legacy.CPUStat("/docker/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := CPUStat{
+		Usage:       12345678901 * time.Nanosecond,
+		User:        8500 * time.Millisecond,
+		System:      3720 * time.Millisecond,
+		NrPeriods:   520,
+		NrThrottled: 14,
+		Throttled:   1500 * time.Millisecond,
+	}
+	if want != s {
+		t.Errorf("want %+v, have %+v", want, s)
+	}
+
+	usage, err := legacy.CPUUsagePerCPU("/docker/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantUsage := []time.Duration{6 * time.Second, 5 * time.Second, 1345678901 * time.Nanosecond, 0}
+	if !reflect.DeepEqual(wantUsage, usage) {
+		t.Errorf("want usage per cpu %v, have %v", wantUsage, usage)
+	}
+
+	m, err := legacy.CPUMax("/docker/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Quota == nil || *m.Quota != 50*time.Millisecond {
+		t.Errorf("want quota %s, have %v", 50*time.Millisecond, m.Quota)
+	}
+	if want, have := 100*time.Millisecond, m.Period; want != have {
+		t.Errorf("want period %s, have %s", want, have)
+	}
+
+	m, err = legacy.CPUMax("/docker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Quota != nil {
+		t.Errorf("want no quota, have %s", *m.Quota)
+	}
+}
+
+func TestLegacyCPUUserHZ(t *testing.T) {
+	proc := procfs.FS("fixtures/legacy/proc-250hz")
+	if err := proc.SetUserHZ(250); err != nil {
+		t.Fatal(err)
+	}
+	fs := LegacyFS{root: "fixtures/legacy", proc: proc}
+
+	s, err := fs.CPUStat("/docker/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 3400*time.Millisecond, s.User; want != have {
+		t.Errorf("want user time %s, have %s", want, have)
+	}
+	if want, have := 1488*time.Millisecond, s.System; want != have {
+		t.Errorf("want system time %s, have %s", want, have)
+	}
+}
+
+func TestLegacyIOStat(t *testing.T) {
+	have, err := legacy.IOStat("/docker/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := IOStat{
+		{Major: 8, Minor: 0}: {
+			ReadBytes:    1459200,
+			WriteBytes:   314773504,
+			ReadIOs:      192,
+			WriteIOs:     353,
+			DiscardBytes: 4096,
+			DiscardIOs:   1,
+		},
+		{Major: 253, Minor: 1}: {
+			ReadBytes:  90112,
+			WriteBytes: 4096,
+			ReadIOs:    22,
+			WriteIOs:   1,
+		},
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("want %+v, have %+v", want, have)
+	}
+}
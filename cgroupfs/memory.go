@@ -0,0 +1,116 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroupfs
+
+// MemoryStat holds the memory usage breakdown of a cgroup. Sizes are in
+// bytes.
+type MemoryStat struct {
+	// Memory used in anonymous mappings.
+	Anon uint64
+	// Memory used to cache filesystem data, including tmpfs and shared
+	// memory.
+	File uint64
+	// Memory allocated to kernel stacks.
+	KernelStack uint64
+	// Memory used for in-kernel data structures.
+	Slab uint64
+	// Memory used in network transmission buffers.
+	Sock uint64
+	// Cached filesystem data that is swap-backed, such as tmpfs and shared
+	// memory.
+	Shmem uint64
+	// Cached filesystem data mapped with mmap.
+	FileMapped uint64
+	// Cached filesystem data that was modified but not yet written back.
+	FileDirty uint64
+	// Cached filesystem data that is being written back.
+	FileWriteback uint64
+	// Number of page faults and major page faults incurred.
+	PgFault    uint64
+	PgMajFault uint64
+
+	// Raw holds every entry of the stat file, keyed by its name.
+	Raw map[string]uint64
+}
+
+// MemoryEvents holds the number of memory events of a cgroup and its
+// descendants.
+type MemoryEvents struct {
+	// Number of times the cgroup was reclaimed even though its usage was
+	// under the low boundary.
+	Low uint64
+	// Number of times processes were throttled and routed to direct reclaim
+	// because the high boundary was exceeded.
+	High uint64
+	// Number of times the usage was about to go over the max boundary.
+	Max uint64
+	// Number of times the usage reached the limit and allocations were about
+	// to fail.
+	OOM uint64
+	// Number of processes killed by the OOM killer.
+	OOMKill uint64
+}
+
+// MemoryCurrent returns the memory usage of the cgroup and its descendants
+// in bytes, read from memory.current.
+func (fs FS) MemoryCurrent(cgroup string) (uint64, error) {
+	return readUint(fs.Path(cgroup, "memory.current"))
+}
+
+// MemoryMax returns the memory usage hard limit of the cgroup in bytes,
+// read from memory.max. It returns nil if the usage is not limited.
+func (fs FS) MemoryMax(cgroup string) (*uint64, error) {
+	return readLimit(fs.Path(cgroup, "memory.max"))
+}
+
+// MemoryStat returns the memory usage breakdown of the cgroup, read from
+// memory.stat.
+func (fs FS) MemoryStat(cgroup string) (MemoryStat, error) {
+	kvs, err := readKeyValues(fs.Path(cgroup, "memory.stat"))
+	if err != nil {
+		return MemoryStat{}, err
+	}
+
+	return MemoryStat{
+		Anon:          kvs["anon"],
+		File:          kvs["file"],
+		KernelStack:   kvs["kernel_stack"],
+		Slab:          kvs["slab"],
+		Sock:          kvs["sock"],
+		Shmem:         kvs["shmem"],
+		FileMapped:    kvs["file_mapped"],
+		FileDirty:     kvs["file_dirty"],
+		FileWriteback: kvs["file_writeback"],
+		PgFault:       kvs["pgfault"],
+		PgMajFault:    kvs["pgmajfault"],
+		Raw:           kvs,
+	}, nil
+}
+
+// MemoryEvents returns the memory events of the cgroup, read from
+// memory.events.
+func (fs FS) MemoryEvents(cgroup string) (MemoryEvents, error) {
+	kvs, err := readKeyValues(fs.Path(cgroup, "memory.events"))
+	if err != nil {
+		return MemoryEvents{}, err
+	}
+
+	return MemoryEvents{
+		Low:     kvs["low"],
+		High:    kvs["high"],
+		Max:     kvs["max"],
+		OOM:     kvs["oom"],
+		OOMKill: kvs["oom_kill"],
+	}, nil
+}
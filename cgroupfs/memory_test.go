@@ -0,0 +1,84 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroupfs
+
+import "testing"
+
+func TestMemoryCurrent(t *testing.T) {
+	have, err := unified.MemoryCurrent("/system.slice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := uint64(1073741824); want != have {
+		t.Errorf("want memory current %d, have %d", want, have)
+	}
+}
+
+func TestMemoryMax(t *testing.T) {
+	max, err := unified.MemoryMax("/system.slice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if max == nil || *max != 2147483648 {
+		t.Errorf("want memory max %d, have %v", 2147483648, max)
+	}
+
+	max, err = unified.MemoryMax("/system.slice/docker-abc.scope")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if max != nil {
+		t.Errorf("want no memory max, have %d", *max)
+	}
+}
+
+func TestMemoryStat(t *testing.T) {
+	s, err := unified.MemoryStat("/system.slice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		want uint64
+		have uint64
+	}{
+		{name: "anon", want: 536870912, have: s.Anon},
+		{name: "file", want: 429496729, have: s.File},
+		{name: "kernel_stack", want: 4423680, have: s.KernelStack},
+		{name: "slab", want: 73400320, have: s.Slab},
+		{name: "sock", want: 16384, have: s.Sock},
+		{name: "shmem", want: 1048576, have: s.Shmem},
+		{name: "file_mapped", want: 104857600, have: s.FileMapped},
+		{name: "file_dirty", want: 135168, have: s.FileDirty},
+		{name: "file_writeback", want: 0, have: s.FileWriteback},
+		{name: "pgfault", want: 18739402, have: s.PgFault},
+		{name: "pgmajfault", want: 1022, have: s.PgMajFault},
+		{name: "anon_thp", want: 209715200, have: s.Raw["anon_thp"]},
+	} {
+		if tt.want != tt.have {
+			t.Errorf("want %s %d, have %d", tt.name, tt.want, tt.have)
+		}
+	}
+}
+
+func TestMemoryEvents(t *testing.T) {
+	have, err := unified.MemoryEvents("/system.slice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (MemoryEvents{High: 12, Max: 3, OOM: 1, OOMKill: 1}); want != have {
+		t.Errorf("want %+v, have %+v", want, have)
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroupfs
+
+// PIDsCurrent returns the number of processes in the cgroup and its
+// descendants, read from pids.current.
+func (fs FS) PIDsCurrent(cgroup string) (uint64, error) {
+	return readUint(fs.Path(cgroup, "pids.current"))
+}
+
+// PIDsMax returns the limit of the number of processes in the cgroup, read
+// from pids.max. It returns nil if the number is not limited.
+func (fs FS) PIDsMax(cgroup string) (*uint64, error) {
+	return readLimit(fs.Path(cgroup, "pids.max"))
+}
@@ -0,0 +1,42 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroupfs
+
+import "testing"
+
+func TestPIDs(t *testing.T) {
+	current, err := unified.PIDsCurrent("/system.slice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := uint64(57); want != current {
+		t.Errorf("want pids current %d, have %d", want, current)
+	}
+
+	max, err := unified.PIDsMax("/system.slice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if max == nil || *max != 4915 {
+		t.Errorf("want pids max %d, have %v", 4915, max)
+	}
+
+	max, err = unified.PIDsMax("/system.slice/docker-abc.scope")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if max != nil {
+		t.Errorf("want no pids max, have %d", *max)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroupfs
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/prometheus/procfs"
+)
+
+// Pressure returns the pressure stall information of the cgroup for the
+// given resource, e.g. "cpu", "memory" or "io", read from
+// <resource>.pressure.
+func (fs FS) Pressure(cgroup, resource string) (procfs.PSIStats, error) {
+	f, err := os.Open(fs.Path(cgroup, resource+".pressure"))
+	if err != nil {
+		return procfs.PSIStats{}, err
+	}
+	defer f.Close()
+
+	psi, err := procfs.ParsePSIStats(f)
+	if err != nil {
+		return procfs.PSIStats{}, fmt.Errorf("couldn't parse %s: %s", f.Name(), err)
+	}
+
+	return psi, nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroupfs
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prometheus/procfs"
+)
+
+func TestPressure(t *testing.T) {
+	for _, tt := range []struct {
+		resource string
+		want     procfs.PSIStats
+	}{
+		{
+			resource: "cpu",
+			want: procfs.PSIStats{
+				Some: &procfs.PSILine{Avg10: 0.1, Avg60: 0.05, Avg300: 0.01, Total: 123456 * time.Microsecond},
+			},
+		},
+		{
+			resource: "io",
+			want: procfs.PSIStats{
+				Some: &procfs.PSILine{Avg10: 1.5, Avg60: 0.75, Avg300: 0.25, Total: 987654 * time.Microsecond},
+				Full: &procfs.PSILine{Avg10: 1.2, Avg60: 0.6, Avg300: 0.2, Total: 876543 * time.Microsecond},
+			},
+		},
+	} {
+		have, err := unified.Pressure("/system.slice", tt.resource)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tt.want, have) {
+			t.Errorf("%s: want %+v, have %+v", tt.resource, tt.want, have)
+		}
+	}
+
+	if _, err := unified.Pressure("/system.slice/sshd.service", "cpu"); !os.IsNotExist(err) {
+		t.Errorf("want not exist error, have %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// Diskstats holds the IO statistics of a single block device, read from
+// /proc/diskstats. See Documentation/iostats.txt in the kernel sources for
+// a detailed explanation of the fields. Times are in milliseconds.
+type Diskstats struct {
+	// Major number of the device.
+	MajorNumber uint32
+	// Minor number of the device.
+	MinorNumber uint32
+	// Name of the device.
+	DeviceName string
+	// Number of reads completed successfully.
+	ReadIOs uint64
+	// Number of adjacent reads merged into one.
+	ReadMerges uint64
+	// Number of sectors read.
+	ReadSectors uint64
+	// Time spent reading.
+	ReadTicks uint64
+	// Number of writes completed successfully.
+	WriteIOs uint64
+	// Number of adjacent writes merged into one.
+	WriteMerges uint64
+	// Number of sectors written.
+	WriteSectors uint64
+	// Time spent writing.
+	WriteTicks uint64
+	// Number of IOs currently in progress.
+	IOsInProgress uint64
+	// Time spent doing IOs.
+	IOsTotalTicks uint64
+	// Time spent doing IOs, weighted by the number of IOs in progress.
+	WeightedIOTicks uint64
+	// Number of discards completed successfully. Available since Linux 4.18.
+	DiscardIOs uint64
+	// Number of adjacent discards merged into one. Available since Linux 4.18.
+	DiscardMerges uint64
+	// Number of sectors discarded. Available since Linux 4.18.
+	DiscardSectors uint64
+	// Time spent discarding. Available since Linux 4.18.
+	DiscardTicks uint64
+	// Number of flush requests completed successfully. Available since
+	// Linux 5.5.
+	FlushRequestsCompleted uint64
+	// Time spent flushing. Available since Linux 5.5.
+	TimeSpentFlushing uint64
+}
+
+// NewDiskstats returns the IO statistics of all block devices read from
+// /proc/diskstats.
+func NewDiskstats() ([]Diskstats, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewDiskstats()
+}
+
+// NewDiskstats returns the IO statistics of all block devices read from
+// /proc/diskstats.
+func (fs FS) NewDiskstats() ([]Diskstats, error) {
+	f, err := os.Open(fs.Path("diskstats"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseDiskstats(f)
+}
+
+func parseDiskstats(r io.Reader) ([]Diskstats, error) {
+	var (
+		diskstats []Diskstats
+		scanner   = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		// Older kernels print 14 fields, 4.18+ add the 4 discard fields and
+		// 5.5+ add the 2 flush fields.
+		switch len(fields) {
+		case 14, 18, 20:
+		default:
+			return nil, fmt.Errorf("invalid number of fields %d in diskstats line: %q", len(fields), scanner.Text())
+		}
+
+		major, err := strconv.ParseUint(fields[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid major number in diskstats: %s", err)
+		}
+		minor, err := strconv.ParseUint(fields[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid minor number in diskstats: %s", err)
+		}
+
+		us, err := util.ParseUint64s(fields[3:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value in diskstats: %s", err)
+		}
+		// Pad the values so absent trailing fields are zero.
+		us = append(us, make([]uint64, 17-len(us))...)
+
+		diskstats = append(diskstats, Diskstats{
+			MajorNumber:            uint32(major),
+			MinorNumber:            uint32(minor),
+			DeviceName:             fields[2],
+			ReadIOs:                us[0],
+			ReadMerges:             us[1],
+			ReadSectors:            us[2],
+			ReadTicks:              us[3],
+			WriteIOs:               us[4],
+			WriteMerges:            us[5],
+			WriteSectors:           us[6],
+			WriteTicks:             us[7],
+			IOsInProgress:          us[8],
+			IOsTotalTicks:          us[9],
+			WeightedIOTicks:        us[10],
+			DiscardIOs:             us[11],
+			DiscardMerges:          us[12],
+			DiscardSectors:         us[13],
+			DiscardTicks:           us[14],
+			FlushRequestsCompleted: us[15],
+			TimeSpentFlushing:      us[16],
+		})
+	}
+
+	return diskstats, scanner.Err()
+}
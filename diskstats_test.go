@@ -0,0 +1,79 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiskstats(t *testing.T) {
+	diskstats, err := FS("fixtures").NewDiskstats()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 6, len(diskstats); want != have {
+		t.Fatalf("want %d diskstats, have %d", want, have)
+	}
+
+	for _, want := range []Diskstats{
+		{
+			MajorNumber: 8, MinorNumber: 0, DeviceName: "sda",
+			ReadIOs: 25354637, ReadMerges: 34367663, ReadSectors: 1003346126, ReadTicks: 18492372,
+			WriteIOs: 28444756, WriteMerges: 11134226, WriteSectors: 505697032, WriteTicks: 63877960,
+			IOsInProgress: 0, IOsTotalTicks: 9653308, WeightedIOTicks: 82621804,
+		},
+		{
+			MajorNumber: 259, MinorNumber: 0, DeviceName: "nvme0n1",
+			ReadIOs: 47114, ReadMerges: 4, ReadSectors: 4643973, ReadTicks: 21650,
+			WriteIOs: 1078320, WriteMerges: 43950, WriteSectors: 39451633, WriteTicks: 1011053,
+			IOsInProgress: 0, IOsTotalTicks: 222766, WeightedIOTicks: 1032546,
+		},
+		{
+			MajorNumber: 253, MinorNumber: 0, DeviceName: "dm-0",
+			ReadIOs: 1678, ReadMerges: 0, ReadSectors: 13416, ReadTicks: 4,
+			WriteIOs: 51, WriteMerges: 0, WriteSectors: 408, WriteTicks: 12,
+			IOsInProgress: 0, IOsTotalTicks: 20, WeightedIOTicks: 16,
+			DiscardIOs: 203, DiscardMerges: 0, DiscardSectors: 1624, DiscardTicks: 8,
+			FlushRequestsCompleted: 96, TimeSpentFlushing: 12,
+		},
+	} {
+		var found bool
+		for _, have := range diskstats {
+			if have.DeviceName != want.DeviceName {
+				continue
+			}
+			found = true
+			if want != have {
+				t.Errorf("%s: want %+v, have %+v", want.DeviceName, want, have)
+			}
+		}
+		if !found {
+			t.Errorf("%s: not found", want.DeviceName)
+		}
+	}
+}
+
+func TestParseDiskstatsInvalid(t *testing.T) {
+	for _, in := range []string{
+		"8 0 sda 1 2 3\n",
+		"8 0 sda 1 2 3 4 5 6 7 8 9 10 x\n",
+		"x 0 sda 1 2 3 4 5 6 7 8 9 10 11\n",
+	} {
+		if _, err := parseDiskstats(strings.NewReader(in)); err == nil {
+			t.Errorf("want error parsing %q, have none", in)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// LoadAvg represents the load averages and scheduling information read from
+// /proc/loadavg.
+type LoadAvg struct {
+	// Load average over the last 1, 5 and 15 minutes.
+	Load1  float64
+	Load5  float64
+	Load15 float64
+	// Number of currently runnable kernel scheduling entities (processes,
+	// threads).
+	Runnable uint64
+	// Number of kernel scheduling entities that currently exist on the
+	// system.
+	Total uint64
+	// PID of the process that was most recently created on the system.
+	LastPID int
+}
+
+// LoadAvg returns the load averages read from /proc/loadavg.
+func (fs FS) LoadAvg() (LoadAvg, error) {
+	data, err := ioutil.ReadFile(fs.Path("loadavg"))
+	if err != nil {
+		return LoadAvg{}, err
+	}
+
+	return parseLoadAvg(string(data))
+}
+
+func parseLoadAvg(data string) (LoadAvg, error) {
+	fields := strings.Fields(data)
+	if len(fields) != 5 {
+		return LoadAvg{}, fmt.Errorf("unexpected loadavg format: %q", data)
+	}
+
+	var (
+		la  LoadAvg
+		err error
+	)
+
+	loads := []*float64{&la.Load1, &la.Load5, &la.Load15}
+	for i, load := range loads {
+		if *load, err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return LoadAvg{}, fmt.Errorf("couldn't parse load %q: %s", fields[i], err)
+		}
+	}
+
+	entities := strings.Split(fields[3], "/")
+	if len(entities) != 2 {
+		return LoadAvg{}, fmt.Errorf("unexpected scheduling entities format: %q", fields[3])
+	}
+	if la.Runnable, err = strconv.ParseUint(entities[0], 10, 64); err != nil {
+		return LoadAvg{}, fmt.Errorf("couldn't parse runnable entities %q: %s", entities[0], err)
+	}
+	if la.Total, err = strconv.ParseUint(entities[1], 10, 64); err != nil {
+		return LoadAvg{}, fmt.Errorf("couldn't parse total entities %q: %s", entities[1], err)
+	}
+
+	if la.LastPID, err = strconv.Atoi(fields[4]); err != nil {
+		return LoadAvg{}, fmt.Errorf("couldn't parse last pid %q: %s", fields[4], err)
+	}
+
+	return la, nil
+}
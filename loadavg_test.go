@@ -0,0 +1,41 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import "testing"
+
+func TestLoadAvg(t *testing.T) {
+	la, err := FS("fixtures").LoadAvg()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := LoadAvg{Load1: 0.02, Load5: 0.04, Load15: 0.05, Runnable: 1, Total: 497, LastPID: 25812}
+	if want != la {
+		t.Errorf("want %+v, have %+v", want, la)
+	}
+}
+
+func TestParseLoadAvgInvalid(t *testing.T) {
+	for _, in := range []string{
+		"0.02 0.04 0.05 1/497\n",
+		"0.02 0.04 x 1/497 25812\n",
+		"0.02 0.04 0.05 1 25812\n",
+		"0.02 0.04 0.05 1/x 25812\n",
+	} {
+		if _, err := parseLoadAvg(in); err == nil {
+			t.Errorf("want error parsing %q, have none", in)
+		}
+	}
+}
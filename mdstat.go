@@ -19,11 +19,20 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 var (
 	statuslineRE = regexp.MustCompile(`(\d+) blocks .*\[(\d+)/(\d+)\] \[[U_]+\]`)
+	sizeRE       = regexp.MustCompile(`^(\d+) blocks`)
+	chunkRE      = regexp.MustCompile(`(\d+)[kK] chunks?\b`)
 	buildlineRE  = regexp.MustCompile(`\((\d+)/\d+\)`)
+	synclineRE   = regexp.MustCompile(`(resync|recovery|reshape|check|repair)\s*=\s*(\S+)`)
+	syncblocksRE = regexp.MustCompile(`\((\d+)/(\d+)\)`)
+	finishRE     = regexp.MustCompile(`finish=([0-9.]+)min`)
+	speedRE      = regexp.MustCompile(`speed=(\d+)K/sec`)
+	bitmapRE     = regexp.MustCompile(`bitmap: (\d+)/(\d+) pages \[(\d+)KB\], (\d+)KB chunk(?:, file: (.*))?`)
+	memberRE     = regexp.MustCompile(`^(.+)\[(\d+)\]((?:\([A-Z]\))*)$`)
 )
 
 // MDStat holds info parsed from /proc/mdstat.
@@ -40,6 +49,70 @@ type MDStat struct {
 	BlocksTotal int64
 	// Number of blocks on the device that are in sync.
 	BlocksSynced int64
+	// The RAID level, e.g. "raid1", "raid0" or "linear". Inactive arrays
+	// may not report it.
+	Level string
+	// The member devices of the array.
+	Devices []MDStatDevice
+	// The chunk size in bytes, or 0 for RAID levels without chunks.
+	ChunkSize int64
+	// The ongoing sync operation, or nil if there is none.
+	Sync *MDStatSync
+	// The write-intent bitmap, or nil if the array has none.
+	Bitmap *MDStatBitmap
+}
+
+// MDStatDevice is a member device of an md array.
+type MDStatDevice struct {
+	// Name of the device, e.g. "sda1".
+	Name string
+	// The role of the device in the array, i.e. its slot number.
+	Role int64
+	// Whether the device is faulty, marked with (F).
+	Failed bool
+	// Whether the device is a spare, marked with (S).
+	Spare bool
+	// Whether the device is write-mostly, marked with (W).
+	WriteMostly bool
+	// Whether the device is a journal device, marked with (J).
+	Journal bool
+	// Whether the device is a replacement for another device, marked with
+	// (R).
+	Replacement bool
+}
+
+// MDStatSync is an ongoing sync operation of an md array.
+type MDStatSync struct {
+	// The operation: "resync", "recovery", "reshape", "check" or "repair".
+	Action string
+	// "DELAYED" or "PENDING" if the operation waits to be started, empty
+	// otherwise.
+	Status string
+	// The progress of the operation in percent.
+	Progress float64
+	// The number of blocks processed, and the total number of blocks to
+	// process.
+	BlocksDone  int64
+	BlocksTotal int64
+	// The estimated time until the operation finishes.
+	Finish time.Duration
+	// The current speed of the operation in bytes per second.
+	Speed int64
+}
+
+// MDStatBitmap is the write-intent bitmap of an md array.
+type MDStatBitmap struct {
+	// The number of pages of the in-memory bitmap which are allocated, and
+	// the total number of pages.
+	PagesUsed  int64
+	PagesTotal int64
+	// The memory used by the allocated pages, in bytes.
+	Size int64
+	// The amount of data covered by each bit, in bytes.
+	ChunkSize int64
+	// The path of the bitmap file, or empty if the bitmap is stored on the
+	// member devices.
+	File string
 }
 
 // ParseMDStat parses an mdstat-file and returns a struct with the relevant infos.
@@ -50,66 +123,276 @@ func (fs FS) ParseMDStat() (mdstates []MDStat, err error) {
 		return []MDStat{}, fmt.Errorf("error parsing %s: %s", mdStatusFilePath, err)
 	}
 
+	mdStates, err := parseMDStat(string(content))
+	if err != nil {
+		return mdStates, fmt.Errorf("error parsing %s: %s", mdStatusFilePath, err)
+	}
+
+	return mdStates, nil
+}
+
+// MDPersonalities returns the RAID levels supported by the kernel, read from
+// the first line of /proc/mdstat, e.g. "raid1" or "linear".
+func (fs FS) MDPersonalities() ([]string, error) {
+	path := fs.Path("mdstat")
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	personalities, err := parseMDPersonalities(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %s", path, err)
+	}
+
+	return personalities, nil
+}
+
+// parseMDPersonalities parses the personalities line, like
+//
+//	Personalities : [linear] [raid0] [raid1]
+func parseMDPersonalities(content string) ([]string, error) {
+	personalities := []string{}
+	for _, l := range strings.Split(content, "\n") {
+		if !strings.HasPrefix(l, "Personalities") {
+			continue
+		}
+
+		fields := strings.Fields(l)
+		if len(fields) < 2 || fields[1] != ":" {
+			return nil, fmt.Errorf("unexpected personalities line %q", l)
+		}
+		for _, p := range fields[2:] {
+			personalities = append(personalities, strings.Trim(p, "[]"))
+		}
+		break
+	}
+
+	return personalities, nil
+}
+
+func parseMDStat(content string) ([]MDStat, error) {
 	mdStates := []MDStat{}
-	lines := strings.Split(string(content), "\n")
+	lines := strings.Split(content, "\n")
 	for i, l := range lines {
 		if l == "" {
 			continue
 		}
-		if l[0] == ' ' {
+		if l[0] == ' ' || l[0] == '\t' {
 			continue
 		}
 		if strings.HasPrefix(l, "Personalities") || strings.HasPrefix(l, "unused") {
 			continue
 		}
 
-		mainLine := strings.Split(l, " ")
-		if len(mainLine) < 3 {
-			return mdStates, fmt.Errorf("error parsing mdline: %s", l)
+		// The array is described by the indented lines following the main
+		// line.
+		var details []string
+		for _, d := range lines[i+1:] {
+			if d == "" || (d[0] != ' ' && d[0] != '\t') {
+				break
+			}
+			if d = strings.TrimSpace(d); d != "" {
+				details = append(details, d)
+			}
+		}
+
+		md, err := parseMDStatArray(l, details)
+		if err != nil {
+			return mdStates, err
 		}
-		mdName := mainLine[0]
-		activityState := mainLine[2]
+		mdStates = append(mdStates, md)
+	}
+
+	return mdStates, nil
+}
+
+// parseMDStatArray parses the main line of an array, like
+//
+//	md0 : active raid1 sdk[2](S) sdi1[0] sdj1[1]
+//
+// and the detail lines following it.
+func parseMDStatArray(mainLine string, details []string) (MDStat, error) {
+	fields := strings.Fields(mainLine)
+	if len(fields) < 3 || fields[1] != ":" {
+		return MDStat{}, fmt.Errorf("error parsing mdline: %s", mainLine)
+	}
+	md := MDStat{
+		Name:          fields[0],
+		ActivityState: fields[2],
+		Devices:       []MDStatDevice{},
+	}
 
-		if len(lines) <= i+3 {
-			return mdStates, fmt.Errorf(
-				"error parsing %s: too few lines for md device %s",
-				mdStatusFilePath,
-				mdName,
-			)
+	for _, f := range fields[3:] {
+		switch {
+		case strings.HasPrefix(f, "("):
+			// Read-only state, e.g. "(auto-read-only)".
+		case !strings.Contains(f, "["):
+			md.Level = f
+		default:
+			dev, err := parseMDStatDevice(f)
+			if err != nil {
+				return MDStat{}, fmt.Errorf("error parsing mdline %s: %s", mainLine, err)
+			}
+			md.Devices = append(md.Devices, dev)
 		}
+	}
+
+	if len(details) == 0 {
+		return MDStat{}, fmt.Errorf("too few lines for md device %s", md.Name)
+	}
 
-		active, total, size, err := evalStatusline(lines[i+1])
+	var err error
+	if statuslineRE.MatchString(details[0]) {
+		md.DisksActive, md.DisksTotal, md.BlocksTotal, err = evalStatusline(details[0])
 		if err != nil {
-			return mdStates, fmt.Errorf("error parsing %s: %s", mdStatusFilePath, err)
+			return MDStat{}, err
+		}
+	} else {
+		// Levels without redundancy, like raid0 and linear, don't report
+		// the number of disks.
+		matches := sizeRE.FindStringSubmatch(details[0])
+		if len(matches) != 2 {
+			return MDStat{}, fmt.Errorf("unexpected statusline: %s", details[0])
+		}
+		if md.BlocksTotal, err = strconv.ParseInt(matches[1], 10, 64); err != nil {
+			return MDStat{}, fmt.Errorf("unexpected statusline %s: %s", details[0], err)
+		}
+		for _, d := range md.Devices {
+			if !d.Failed && !d.Spare {
+				md.DisksActive++
+			}
 		}
+		md.DisksTotal = int64(len(md.Devices))
+	}
 
-		// j is the line number of the syncing-line.
-		j := i + 2
-		if strings.Contains(lines[i+2], "bitmap") { // skip bitmap line
-			j = i + 3
+	if matches := chunkRE.FindStringSubmatch(details[0]); len(matches) == 2 {
+		chunk, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return MDStat{}, fmt.Errorf("unexpected statusline %s: %s", details[0], err)
 		}
+		md.ChunkSize = chunk * 1024
+	}
 
-		// If device is syncing at the moment, get the number of currently
-		// synced bytes, otherwise that number equals the size of the device.
-		syncedBlocks := size
-		if strings.Contains(lines[j], "recovery") || strings.Contains(lines[j], "resync") {
-			syncedBlocks, err = evalBuildline(lines[j])
-			if err != nil {
-				return mdStates, fmt.Errorf("error parsing %s: %s", mdStatusFilePath, err)
+	// If device is syncing at the moment, get the number of currently
+	// synced bytes, otherwise that number equals the size of the device.
+	md.BlocksSynced = md.BlocksTotal
+	for _, d := range details[1:] {
+		switch {
+		case strings.HasPrefix(d, "bitmap:"):
+			if md.Bitmap, err = parseMDStatBitmap(d); err != nil {
+				return MDStat{}, err
+			}
+		case synclineRE.MatchString(d):
+			if md.Sync, err = parseMDStatSync(d); err != nil {
+				return MDStat{}, err
+			}
+			if (md.Sync.Action == "recovery" || md.Sync.Action == "resync") && md.Sync.Status == "" {
+				if md.BlocksSynced, err = evalBuildline(d); err != nil {
+					return MDStat{}, err
+				}
 			}
 		}
+	}
+
+	return md, nil
+}
 
-		mdStates = append(mdStates, MDStat{
-			Name:          mdName,
-			ActivityState: activityState,
-			DisksActive:   active,
-			DisksTotal:    total,
-			BlocksTotal:   size,
-			BlocksSynced:  syncedBlocks,
-		})
+// parseMDStatDevice parses a member device, like "sdb1[1](W)(F)".
+func parseMDStatDevice(s string) (MDStatDevice, error) {
+	matches := memberRE.FindStringSubmatch(s)
+	if len(matches) != 4 {
+		return MDStatDevice{}, fmt.Errorf("unexpected device %q", s)
 	}
 
-	return mdStates, nil
+	role, err := strconv.ParseInt(matches[2], 10, 64)
+	if err != nil {
+		return MDStatDevice{}, fmt.Errorf("unexpected device %q: %s", s, err)
+	}
+
+	flags := matches[3]
+	return MDStatDevice{
+		Name:        matches[1],
+		Role:        role,
+		Failed:      strings.Contains(flags, "(F)"),
+		Spare:       strings.Contains(flags, "(S)"),
+		WriteMostly: strings.Contains(flags, "(W)"),
+		Journal:     strings.Contains(flags, "(J)"),
+		Replacement: strings.Contains(flags, "(R)"),
+	}, nil
+}
+
+// parseMDStatSync parses a sync line, like
+//
+//	[=>...................]  recovery =  8.5% (16775552/195310144) finish=17.0min speed=259783K/sec
+//
+// or a line of a delayed operation, like "resync=DELAYED".
+func parseMDStatSync(line string) (*MDStatSync, error) {
+	matches := synclineRE.FindStringSubmatch(line)
+	s := &MDStatSync{Action: matches[1]}
+
+	if !strings.HasSuffix(matches[2], "%") {
+		s.Status = matches[2]
+		return s, nil
+	}
+
+	var err error
+	if s.Progress, err = strconv.ParseFloat(strings.TrimSuffix(matches[2], "%"), 64); err != nil {
+		return nil, fmt.Errorf("unexpected syncline %s: %s", line, err)
+	}
+
+	if m := syncblocksRE.FindStringSubmatch(line); len(m) == 3 {
+		if s.BlocksDone, err = strconv.ParseInt(m[1], 10, 64); err != nil {
+			return nil, fmt.Errorf("unexpected syncline %s: %s", line, err)
+		}
+		if s.BlocksTotal, err = strconv.ParseInt(m[2], 10, 64); err != nil {
+			return nil, fmt.Errorf("unexpected syncline %s: %s", line, err)
+		}
+	}
+	if m := finishRE.FindStringSubmatch(line); len(m) == 2 {
+		minutes, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected syncline %s: %s", line, err)
+		}
+		s.Finish = time.Duration(minutes * float64(time.Minute))
+	}
+	if m := speedRE.FindStringSubmatch(line); len(m) == 2 {
+		speed, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected syncline %s: %s", line, err)
+		}
+		s.Speed = speed * 1024
+	}
+
+	return s, nil
+}
+
+// parseMDStatBitmap parses a bitmap line, like
+//
+//	bitmap: 0/30 pages [0KB], 65536KB chunk
+func parseMDStatBitmap(line string) (*MDStatBitmap, error) {
+	matches := bitmapRE.FindStringSubmatch(line)
+	if len(matches) != 6 {
+		return nil, fmt.Errorf("unexpected bitmap line: %s", line)
+	}
+
+	var (
+		values [4]int64
+		err    error
+	)
+	for i := range values {
+		if values[i], err = strconv.ParseInt(matches[i+1], 10, 64); err != nil {
+			return nil, fmt.Errorf("unexpected bitmap line %s: %s", line, err)
+		}
+	}
+
+	return &MDStatBitmap{
+		PagesUsed:  values[0],
+		PagesTotal: values[1],
+		Size:       values[2] * 1024,
+		ChunkSize:  values[3] * 1024,
+		File:       matches[5],
+	}, nil
 }
 
 func evalStatusline(statusline string) (active, total, size int64, err error) {
@@ -14,7 +14,9 @@
 package procfs
 
 import (
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestMDStat(t *testing.T) {
@@ -23,22 +25,205 @@ func TestMDStat(t *testing.T) {
 		t.Fatalf("parsing of reference-file failed entirely: %s", err)
 	}
 
-	refs := map[string]MDStat{
-		"md3":   {"md3", "active", 8, 8, 5853468288, 5853468288},
-		"md127": {"md127", "active", 2, 2, 312319552, 312319552},
-		"md0":   {"md0", "active", 2, 2, 248896, 248896},
-		"md4":   {"md4", "inactive", 2, 2, 4883648, 4883648},
-		"md6":   {"md6", "active", 1, 2, 195310144, 16775552},
-		"md8":   {"md8", "active", 2, 2, 195310144, 16775552},
-		"md7":   {"md7", "active", 3, 4, 7813735424, 7813735424},
+	type summary struct {
+		activityState string
+		disksActive   int64
+		disksTotal    int64
+		blocksTotal   int64
+		blocksSynced  int64
+		level         string
+	}
+	refs := map[string]summary{
+		"md3":   {"active", 8, 8, 5853468288, 5853468288, "raid6"},
+		"md127": {"active", 2, 2, 312319552, 312319552, "raid1"},
+		"md0":   {"active", 2, 2, 248896, 248896, "raid1"},
+		"md4":   {"inactive", 2, 2, 4883648, 4883648, "raid1"},
+		"md6":   {"active", 1, 2, 195310144, 16775552, "raid1"},
+		"md8":   {"active", 2, 2, 195310144, 16775552, "raid1"},
+		"md7":   {"active", 3, 4, 7813735424, 7813735424, "raid6"},
+		"md9":   {"active", 4, 4, 523968, 523968, "raid1"},
+		"md10":  {"active", 2, 2, 314159265, 314159265, "raid0"},
+		"md11":  {"active", 2, 2, 4190208, 4190208, "raid1"},
+		"md12":  {"active", 4, 4, 976506880, 976506880, "raid10"},
+		"md219": {"active", 3, 3, 7932, 7932, "linear"},
 	}
 
 	if want, have := len(refs), len(mdStates); want != have {
 		t.Errorf("want %d parsed md-devices, have %d", want, have)
 	}
 	for _, md := range mdStates {
-		if want, have := refs[md.Name], md; want != have {
+		have := summary{md.ActivityState, md.DisksActive, md.DisksTotal, md.BlocksTotal, md.BlocksSynced, md.Level}
+		if want := refs[md.Name]; want != have {
 			t.Errorf("%s: want %v, have %v", md.Name, want, have)
 		}
 	}
 }
+
+func TestMDStatDevices(t *testing.T) {
+	mdStates := testMDStat(t)
+
+	for _, tt := range []struct {
+		name string
+		want []MDStatDevice
+	}{
+		{
+			name: "md0",
+			want: []MDStatDevice{
+				{Name: "sdk", Role: 2, Spare: true},
+				{Name: "sdi1", Role: 0},
+				{Name: "sdj1", Role: 1},
+			},
+		},
+		{
+			name: "md9",
+			want: []MDStatDevice{
+				{Name: "sdc2", Role: 2},
+				{Name: "sdd2", Role: 3},
+				{Name: "sdb2", Role: 1},
+				{Name: "sda2", Role: 0},
+				{Name: "sde", Role: 4, Failed: true},
+				{Name: "sdf", Role: 5, Failed: true},
+				{Name: "sdg", Role: 6, Spare: true},
+			},
+		},
+		{
+			name: "md11",
+			want: []MDStatDevice{
+				{Name: "sdb2", Role: 0},
+				{Name: "sdc2", Role: 1, WriteMostly: true},
+				{Name: "sdd2", Role: 2, Journal: true},
+				{Name: "sde2", Role: 3, Replacement: true},
+			},
+		},
+	} {
+		if have := mdStates[tt.name].Devices; !reflect.DeepEqual(tt.want, have) {
+			t.Errorf("%s: want devices %+v, have %+v", tt.name, tt.want, have)
+		}
+	}
+}
+
+func TestMDStatChunkSize(t *testing.T) {
+	mdStates := testMDStat(t)
+
+	for name, want := range map[string]int64{
+		"md3":   64 * 1024,
+		"md7":   512 * 1024,
+		"md10":  64 * 1024,
+		"md12":  512 * 1024,
+		"md127": 0,
+		"md219": 0,
+	} {
+		if have := mdStates[name].ChunkSize; want != have {
+			t.Errorf("%s: want chunk size %d, have %d", name, want, have)
+		}
+	}
+}
+
+func TestMDStatSync(t *testing.T) {
+	mdStates := testMDStat(t)
+
+	for name, want := range map[string]*MDStatSync{
+		"md6": {
+			Action:      "recovery",
+			Progress:    8.5,
+			BlocksDone:  16775552,
+			BlocksTotal: 195310144,
+			Finish:      17 * time.Minute,
+			Speed:       259783 * 1024,
+		},
+		"md8": {
+			Action:      "resync",
+			Progress:    8.5,
+			BlocksDone:  16775552,
+			BlocksTotal: 195310144,
+			Finish:      17 * time.Minute,
+			Speed:       259783 * 1024,
+		},
+		"md9": {
+			Action: "resync",
+			Status: "DELAYED",
+		},
+		"md12": {
+			Action:      "check",
+			Progress:    10.3,
+			BlocksDone:  100780000,
+			BlocksTotal: 976506880,
+			Finish:      125*time.Minute + 30*time.Second,
+			Speed:       116332 * 1024,
+		},
+		"md3": nil,
+	} {
+		if have := mdStates[name].Sync; !reflect.DeepEqual(want, have) {
+			t.Errorf("%s: want sync %+v, have %+v", name, want, have)
+		}
+	}
+}
+
+func TestMDStatBitmap(t *testing.T) {
+	mdStates := testMDStat(t)
+
+	for name, want := range map[string]*MDStatBitmap{
+		"md7": {
+			PagesUsed:  0,
+			PagesTotal: 30,
+			Size:       0,
+			ChunkSize:  65536 * 1024,
+		},
+		"md11": {
+			PagesUsed:  1,
+			PagesTotal: 1,
+			Size:       4 * 1024,
+			ChunkSize:  65536 * 1024,
+			File:       "/var/md11-bitmap",
+		},
+		"md3": nil,
+	} {
+		if have := mdStates[name].Bitmap; !reflect.DeepEqual(want, have) {
+			t.Errorf("%s: want bitmap %+v, have %+v", name, want, have)
+		}
+	}
+}
+
+func TestMDPersonalities(t *testing.T) {
+	have, err := FS("fixtures").MDPersonalities()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"linear", "multipath", "raid0", "raid1", "raid6", "raid5", "raid4", "raid10"}
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("want personalities %v, have %v", want, have)
+	}
+}
+
+func TestParseMDPersonalitiesInvalid(t *testing.T) {
+	for _, content := range []string{
+		"Personalities",
+		"Personalities [raid1]",
+	} {
+		if _, err := parseMDPersonalities(content); err == nil {
+			t.Errorf("want error parsing %q, have none", content)
+		}
+	}
+
+	have, err := parseMDPersonalities("Personalities : \nunused devices: <none>\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(have) != 0 {
+		t.Errorf("want no personalities, have %v", have)
+	}
+}
+
+func testMDStat(t *testing.T) map[string]MDStat {
+	mdStates, err := FS("fixtures").ParseMDStat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byName := map[string]MDStat{}
+	for _, md := range mdStates {
+		byName[md.Name] = md
+	}
+	return byName
+}
@@ -0,0 +1,301 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Meminfo represents memory statistics read from /proc/meminfo. All sizes
+// are normalized to bytes; the HugePages_* fields are page counts.
+//
+// Fields which depend on the kernel version or configuration are pointers,
+// which are nil if the field is not present in the file.
+type Meminfo struct {
+	// Total usable RAM, i.e. physical RAM minus a few reserved bits and the
+	// kernel binary code.
+	MemTotal uint64
+	// Amount of RAM left unused by the system.
+	MemFree uint64
+	// Estimate of how much memory is available for starting new
+	// applications, without swapping. Available since Linux 3.14.
+	MemAvailable *uint64
+	// Relatively temporary storage for raw disk blocks.
+	Buffers uint64
+	// In-memory cache for files read from the disk. Doesn't include
+	// SwapCached.
+	Cached uint64
+	// Memory that once was swapped out, is swapped back in but still also
+	// is in the swap file.
+	SwapCached uint64
+	// Memory that has been used more recently and usually not reclaimed
+	// unless absolutely necessary.
+	Active uint64
+	// Memory which has been less recently used and is more eligible to be
+	// reclaimed for other purposes.
+	Inactive     uint64
+	ActiveAnon   *uint64
+	InactiveAnon *uint64
+	ActiveFile   *uint64
+	InactiveFile *uint64
+	Unevictable  *uint64
+	Mlocked      *uint64
+	HighTotal    *uint64
+	HighFree     *uint64
+	LowTotal     *uint64
+	LowFree      *uint64
+	MmapCopy     *uint64
+	// Total amount of swap space available.
+	SwapTotal uint64
+	// Amount of swap space that is currently unused.
+	SwapFree uint64
+	// Memory which is waiting to get written back to the disk.
+	Dirty uint64
+	// Memory which is actively being written back to the disk.
+	Writeback uint64
+	// Non-file backed pages mapped into userspace page tables.
+	AnonPages uint64
+	// Files which have been mapped, such as libraries.
+	Mapped uint64
+	// Amount of memory used by tmpfs and shared memory.
+	Shmem *uint64
+	// Kernel allocations the kernel will attempt to reclaim under memory
+	// pressure.
+	KReclaimable *uint64
+	// In-kernel data structures cache.
+	Slab uint64
+	// Part of Slab that might be reclaimed, such as caches.
+	SReclaimable *uint64
+	// Part of Slab that cannot be reclaimed on memory pressure.
+	SUnreclaim *uint64
+	// Memory used by the kernel stacks of all tasks.
+	KernelStack *uint64
+	// Amount of memory dedicated to the lowest level of page tables.
+	PageTables uint64
+	// Network File System pages sent to the server, but not yet committed
+	// to stable storage.
+	NFSUnstable uint64
+	// Memory used for block device bounce buffers.
+	Bounce uint64
+	// Memory used by FUSE for temporary writeback buffers.
+	WritebackTmp *uint64
+	// Total amount of memory currently available to be allocated on the
+	// system, based on the overcommit ratio.
+	CommitLimit *uint64
+	// Amount of memory presently allocated on the system.
+	CommittedAS uint64
+	// Total size of vmalloc memory area.
+	VmallocTotal uint64
+	// Amount of vmalloc area which is used.
+	VmallocUsed uint64
+	// Largest contiguous block of vmalloc area which is free.
+	VmallocChunk uint64
+	// Memory allocated for the per-cpu allocator.
+	Percpu            *uint64
+	HardwareCorrupted *uint64
+	// Non-file backed huge pages mapped into userspace page tables.
+	AnonHugePages *uint64
+	// Memory used by shared memory and tmpfs allocated with huge pages.
+	ShmemHugePages *uint64
+	// Shared memory mapped into userspace with huge pages.
+	ShmemPmdMapped *uint64
+	// Total amount of memory reserved for the contiguous memory allocator.
+	CmaTotal *uint64
+	// Free memory in the contiguous memory allocator.
+	CmaFree *uint64
+	// Size of the pool of huge pages, in pages.
+	HugePagesTotal *uint64
+	// Number of huge pages in the pool that are not yet allocated.
+	HugePagesFree *uint64
+	// Number of huge pages committed for allocation, but not yet allocated.
+	HugePagesRsvd *uint64
+	// Number of huge pages in the pool above the value in
+	// /proc/sys/vm/nr_hugepages.
+	HugePagesSurp *uint64
+	// Default size of huge pages.
+	Hugepagesize *uint64
+	// Total amount of memory consumed by huge pages of all sizes.
+	Hugetlb     *uint64
+	DirectMap4k *uint64
+	DirectMap2M *uint64
+	DirectMap4M *uint64
+	DirectMap1G *uint64
+}
+
+// Meminfo returns an information about current kernel/system memory
+// statistics read from /proc/meminfo.
+func (fs FS) Meminfo() (Meminfo, error) {
+	f, err := os.Open(fs.Path("meminfo"))
+	if err != nil {
+		return Meminfo{}, err
+	}
+	defer f.Close()
+
+	m, err := parseMeminfo(f)
+	if err != nil {
+		return Meminfo{}, fmt.Errorf("couldn't parse %s: %s", f.Name(), err)
+	}
+
+	return *m, nil
+}
+
+func parseMeminfo(r io.Reader) (*Meminfo, error) {
+	var m Meminfo
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		// Each line has at least a name and value; we ignore the unit.
+		fields := strings.Fields(s.Text())
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("malformed meminfo line: %q", s.Text())
+		}
+
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		// The only unit used by the kernel is kB, which is really KiB.
+		if len(fields) == 3 {
+			if fields[2] != "kB" {
+				return nil, fmt.Errorf("unexpected unit %q in meminfo line: %q", fields[2], s.Text())
+			}
+			v *= 1024
+		}
+
+		switch fields[0] {
+		case "MemTotal:":
+			m.MemTotal = v
+		case "MemFree:":
+			m.MemFree = v
+		case "MemAvailable:":
+			m.MemAvailable = &v
+		case "Buffers:":
+			m.Buffers = v
+		case "Cached:":
+			m.Cached = v
+		case "SwapCached:":
+			m.SwapCached = v
+		case "Active:":
+			m.Active = v
+		case "Inactive:":
+			m.Inactive = v
+		case "Active(anon):":
+			m.ActiveAnon = &v
+		case "Inactive(anon):":
+			m.InactiveAnon = &v
+		case "Active(file):":
+			m.ActiveFile = &v
+		case "Inactive(file):":
+			m.InactiveFile = &v
+		case "Unevictable:":
+			m.Unevictable = &v
+		case "Mlocked:":
+			m.Mlocked = &v
+		case "HighTotal:":
+			m.HighTotal = &v
+		case "HighFree:":
+			m.HighFree = &v
+		case "LowTotal:":
+			m.LowTotal = &v
+		case "LowFree:":
+			m.LowFree = &v
+		case "MmapCopy:":
+			m.MmapCopy = &v
+		case "SwapTotal:":
+			m.SwapTotal = v
+		case "SwapFree:":
+			m.SwapFree = v
+		case "Dirty:":
+			m.Dirty = v
+		case "Writeback:":
+			m.Writeback = v
+		case "AnonPages:":
+			m.AnonPages = v
+		case "Mapped:":
+			m.Mapped = v
+		case "Shmem:":
+			m.Shmem = &v
+		case "KReclaimable:":
+			m.KReclaimable = &v
+		case "Slab:":
+			m.Slab = v
+		case "SReclaimable:":
+			m.SReclaimable = &v
+		case "SUnreclaim:":
+			m.SUnreclaim = &v
+		case "KernelStack:":
+			m.KernelStack = &v
+		case "PageTables:":
+			m.PageTables = v
+		case "NFS_Unstable:":
+			m.NFSUnstable = v
+		case "Bounce:":
+			m.Bounce = v
+		case "WritebackTmp:":
+			m.WritebackTmp = &v
+		case "CommitLimit:":
+			m.CommitLimit = &v
+		case "Committed_AS:":
+			m.CommittedAS = v
+		case "VmallocTotal:":
+			m.VmallocTotal = v
+		case "VmallocUsed:":
+			m.VmallocUsed = v
+		case "VmallocChunk:":
+			m.VmallocChunk = v
+		case "Percpu:":
+			m.Percpu = &v
+		case "HardwareCorrupted:":
+			m.HardwareCorrupted = &v
+		case "AnonHugePages:":
+			m.AnonHugePages = &v
+		case "ShmemHugePages:":
+			m.ShmemHugePages = &v
+		case "ShmemPmdMapped:":
+			m.ShmemPmdMapped = &v
+		case "CmaTotal:":
+			m.CmaTotal = &v
+		case "CmaFree:":
+			m.CmaFree = &v
+		case "HugePages_Total:":
+			m.HugePagesTotal = &v
+		case "HugePages_Free:":
+			m.HugePagesFree = &v
+		case "HugePages_Rsvd:":
+			m.HugePagesRsvd = &v
+		case "HugePages_Surp:":
+			m.HugePagesSurp = &v
+		case "Hugepagesize:":
+			m.Hugepagesize = &v
+		case "Hugetlb:":
+			m.Hugetlb = &v
+		case "DirectMap4k:":
+			m.DirectMap4k = &v
+		case "DirectMap2M:":
+			m.DirectMap2M = &v
+		case "DirectMap4M:":
+			m.DirectMap4M = &v
+		case "DirectMap1G:":
+			m.DirectMap1G = &v
+		}
+	}
+
+	return &m, s.Err()
+}
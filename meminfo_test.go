@@ -0,0 +1,86 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMeminfo(t *testing.T) {
+	m, err := FS("fixtures").Meminfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name string
+		want uint64
+		have uint64
+	}{
+		{name: "MemTotal", want: 15666184 * 1024, have: m.MemTotal},
+		{name: "MemFree", want: 440324 * 1024, have: m.MemFree},
+		{name: "Buffers", want: 1020128 * 1024, have: m.Buffers},
+		{name: "Dirty", want: 768 * 1024, have: m.Dirty},
+		{name: "CommittedAS", want: 8689888 * 1024, have: m.CommittedAS},
+		{name: "VmallocTotal", want: 34359738367 * 1024, have: m.VmallocTotal},
+	} {
+		if test.want != test.have {
+			t.Errorf("want %s %d, have %d", test.name, test.want, test.have)
+		}
+	}
+
+	for _, test := range []struct {
+		name string
+		want uint64
+		have *uint64
+	}{
+		{name: "MemAvailable", want: 7312376 * 1024, have: m.MemAvailable},
+		{name: "ActiveFile", want: 2041532 * 1024, have: m.ActiveFile},
+		{name: "CmaTotal", want: 0, have: m.CmaTotal},
+		{name: "HugePagesTotal", want: 0, have: m.HugePagesTotal},
+		{name: "Hugepagesize", want: 2048 * 1024, have: m.Hugepagesize},
+		{name: "DirectMap2M", want: 16039936 * 1024, have: m.DirectMap2M},
+	} {
+		if test.have == nil {
+			t.Errorf("want %s %d, have nil", test.name, test.want)
+			continue
+		}
+		if test.want != *test.have {
+			t.Errorf("want %s %d, have %d", test.name, test.want, *test.have)
+		}
+	}
+
+	for name, have := range map[string]*uint64{
+		"HighTotal":    m.HighTotal,
+		"KReclaimable": m.KReclaimable,
+		"DirectMap1G":  m.DirectMap1G,
+	} {
+		if have != nil {
+			t.Errorf("want %s to be absent, have %d", name, *have)
+		}
+	}
+}
+
+func TestParseMeminfoErrors(t *testing.T) {
+	for _, in := range []string{
+		"MemTotal:\n",
+		"MemTotal: abc kB\n",
+		"MemTotal: 1024 MB\n",
+	} {
+		if _, err := parseMeminfo(strings.NewReader(in)); err == nil {
+			t.Errorf("want error parsing %q, have none", in)
+		}
+	}
+}
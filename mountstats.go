@@ -68,6 +68,35 @@ type MountStatsNFS struct {
 	StatVersion string
 	// The age of the NFS mount.
 	Age time.Duration
+	// The mount options, e.g. "vers" or "hard". Options without a value map
+	// to the empty string. It is nil if no options are reported.
+	Options map[string]string
+	// The NFS protocol version, e.g. "3" or "4.1".
+	Version string
+	// The transport protocol, e.g. "tcp" or "rdma".
+	Protocol string
+	// The maximum number of bytes per read and write request.
+	ReadSize  uint64
+	WriteSize uint64
+	// The time the client waits for a response before it retransmits a
+	// request.
+	Timeout time.Duration
+	// The number of retransmissions before the client takes further recovery
+	// action.
+	Retransmissions uint64
+	// Whether the mount is soft, i.e. requests fail after the retransmissions
+	// instead of being retried indefinitely.
+	Soft bool
+	// The security flavor, e.g. "sys" or "krb5p".
+	SecurityFlavor string
+	// The bitmask of the capabilities of the server, see NFS_CAP_* in the
+	// kernel.
+	Capabilities uint64
+	// The NFSv4 state of the mount, or nil if it is not reported.
+	NFSv4 *NFSv4Info
+	// Statistics about the use of FS-Cache, or nil if the mount does not use
+	// it.
+	FSCache *NFSFSCacheStats
 	// Statistics related to byte counters for various operations.
 	Bytes NFSBytesStats
 	// Statistics related to various NFS event occurrences.
@@ -81,6 +110,20 @@ type MountStatsNFS struct {
 // mountStats implements MountStats.
 func (m MountStatsNFS) mountStats() {}
 
+// ErrorRates returns the error rate of each operation of the mount, keyed by
+// the operation name. Operations without any requests are omitted.
+func (m MountStatsNFS) ErrorRates() map[string]float64 {
+	rates := make(map[string]float64)
+	for _, o := range m.Operations {
+		if o.Requests == 0 {
+			continue
+		}
+		rates[o.Operation] = o.ErrorRate()
+	}
+
+	return rates
+}
+
 // A NFSBytesStats contains statistics about the number of bytes read and written
 // by an NFS client to and from an NFS server.
 type NFSBytesStats struct {
@@ -181,6 +224,53 @@ type NFSOperationStats struct {
 	CumulativeTotalResponseTime time.Duration
 	// Duration from when a request was enqueued to when it was completely handled.
 	CumulativeTotalRequestTime time.Duration
+	// Number of requests that completed with an error status. Only reported
+	// by kernel 5.3 and later, 0 otherwise.
+	Errors uint64
+}
+
+// ErrorRate returns the fraction of requests for the operation which
+// completed with an error status, or 0 if no requests were performed.
+func (o NFSOperationStats) ErrorRate() float64 {
+	if o.Requests == 0 {
+		return 0
+	}
+	return float64(o.Errors) / float64(o.Requests)
+}
+
+// A NFSv4Info contains the NFSv4 state of a mount, parsed from the nfsv4
+// line.
+type NFSv4Info struct {
+	// The bitmasks of the file attributes supported by the server, bm0 to
+	// bm2.
+	AttributeBitmask [3]uint64
+	// The bitmask of the ACL types supported by the server.
+	ACL uint64
+	// Whether the mount uses NFSv4.1 sessions.
+	Sessions bool
+	// The pNFS layout driver, e.g. "LAYOUT_NFSV4_1_FILES", or "not
+	// configured".
+	PNFS string
+	// The lease time of the client.
+	LeaseTime time.Duration
+	// The time since the lease of the client expired, or 0 if it has not
+	// expired.
+	LeaseExpired time.Duration
+}
+
+// A NFSFSCacheStats contains statistics about the use of FS-Cache by a
+// mount, parsed from the fsc line.
+type NFSFSCacheStats struct {
+	// Number of pages read from the cache.
+	PagesReadOK uint64
+	// Number of pages which failed to be read from the cache.
+	PagesReadFail uint64
+	// Number of pages written to the cache.
+	PagesWrittenOK uint64
+	// Number of pages which could not be written to the cache.
+	PagesNotWritten uint64
+	// Number of pages removed from the cache.
+	PagesUncached uint64
 }
 
 // A NFSTransportStats contains statistics for the NFS mount RPC requests and
@@ -312,7 +402,12 @@ func parseMount(ss []string) (*Mount, error) {
 func parseMountStatsNFS(s *bufio.Scanner, statVersion string) (*MountStatsNFS, error) {
 	// Field indicators for parsing specific types of data
 	const (
+		fieldOpts       = "opts:"
 		fieldAge        = "age:"
+		fieldCaps       = "caps:"
+		fieldNFSv4      = "nfsv4:"
+		fieldSec        = "sec:"
+		fieldFsc        = "fsc:"
 		fieldBytes      = "bytes:"
 		fieldEvents     = "events:"
 		fieldPerOpStats = "per-op"
@@ -328,11 +423,17 @@ func parseMountStatsNFS(s *bufio.Scanner, statVersion string) (*MountStatsNFS, e
 		if len(ss) == 0 {
 			break
 		}
-		if len(ss) < 2 {
+		// The fsc line has no values on kernels which no longer count
+		// FS-Cache events.
+		if len(ss) < 2 && ss[0] != fieldFsc {
 			return nil, fmt.Errorf("not enough information for NFS stats: %v", ss)
 		}
 
 		switch ss[0] {
+		case fieldOpts:
+			if err := parseNFSMountOptions(stats, ss[1]); err != nil {
+				return nil, err
+			}
 		case fieldAge:
 			// Age integer is in seconds
 			d, err := time.ParseDuration(ss[1] + "s")
@@ -341,6 +442,44 @@ func parseMountStatsNFS(s *bufio.Scanner, statVersion string) (*MountStatsNFS, e
 			}
 
 			stats.Age = d
+		case fieldCaps:
+			caps := parseMountOptions(ss[1])
+			if v, ok := caps["caps"]; ok {
+				n, err := strconv.ParseUint(v, 0, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid NFS capabilities: %v", ss)
+				}
+
+				stats.Capabilities = n
+			}
+		case fieldNFSv4:
+			// The pNFS layout driver may contain spaces, e.g. "not
+			// configured".
+			info, err := parseNFSv4Info(strings.Join(ss[1:], " "))
+			if err != nil {
+				return nil, err
+			}
+
+			stats.NFSv4 = info
+		case fieldFsc:
+			fstats, err := parseNFSFSCacheStats(ss[1:])
+			if err != nil {
+				return nil, err
+			}
+
+			stats.FSCache = fstats
+		case fieldSec:
+			// Older kernels don't report the flavor in the mount options.
+			// For Kerberos, flavor is RPC_AUTH_GSS and the pseudoflavor
+			// identifies the security flavor.
+			sec := parseMountOptions(ss[1])
+			flavor, ok := nfsSecurityFlavors[sec["pseudoflavor"]]
+			if !ok {
+				flavor, ok = nfsSecurityFlavors[sec["flavor"]]
+			}
+			if ok && stats.SecurityFlavor == "" {
+				stats.SecurityFlavor = flavor
+			}
 		case fieldBytes:
 			bstats, err := parseNFSBytesStats(ss[1:])
 			if err != nil {
@@ -392,6 +531,144 @@ func parseMountStatsNFS(s *bufio.Scanner, statVersion string) (*MountStatsNFS, e
 	return stats, nil
 }
 
+// nfsSecurityFlavors maps the RPC authentication flavor and pseudoflavor
+// numbers of the sec line to the names used in the mount options.
+var nfsSecurityFlavors = map[string]string{
+	"0":      "none",
+	"1":      "sys",
+	"390003": "krb5",
+	"390004": "krb5i",
+	"390005": "krb5p",
+}
+
+// parseNFSMountOptions parses the comma separated mount options of the opts
+// line into stats.
+func parseNFSMountOptions(stats *MountStatsNFS, s string) error {
+	stats.Options = parseMountOptions(s)
+
+	for _, f := range []struct {
+		name string
+		v    *uint64
+	}{
+		{name: "rsize", v: &stats.ReadSize},
+		{name: "wsize", v: &stats.WriteSize},
+		{name: "retrans", v: &stats.Retransmissions},
+	} {
+		v, ok := stats.Options[f.name]
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid NFS mount option %s=%s: %s", f.name, v, err)
+		}
+
+		*f.v = n
+	}
+
+	// timeo is in tenths of a second.
+	if v, ok := stats.Options["timeo"]; ok {
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid NFS mount option timeo=%s: %s", v, err)
+		}
+
+		stats.Timeout = time.Duration(n) * time.Second / 10
+	}
+
+	stats.Version = stats.Options["vers"]
+	stats.Protocol = stats.Options["proto"]
+	stats.SecurityFlavor = stats.Options["sec"]
+	_, soft := stats.Options["soft"]
+	_, softErr := stats.Options["softerr"]
+	stats.Soft = soft || softErr
+
+	return nil
+}
+
+// parseNFSv4Info parses the comma separated values of the nfsv4 line.
+func parseNFSv4Info(s string) (*NFSv4Info, error) {
+	values := parseMountOptions(s)
+
+	info := &NFSv4Info{
+		PNFS: values["pnfs"],
+	}
+	_, info.Sessions = values["sessions"]
+
+	for _, f := range []struct {
+		name string
+		v    *uint64
+	}{
+		{name: "bm0", v: &info.AttributeBitmask[0]},
+		{name: "bm1", v: &info.AttributeBitmask[1]},
+		{name: "bm2", v: &info.AttributeBitmask[2]},
+		{name: "acl", v: &info.ACL},
+	} {
+		v, ok := values[f.name]
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.ParseUint(v, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid NFSv4 info %s=%s: %s", f.name, v, err)
+		}
+
+		*f.v = n
+	}
+
+	// The lease times are in seconds.
+	for _, f := range []struct {
+		name string
+		d    *time.Duration
+	}{
+		{name: "lease_time", d: &info.LeaseTime},
+		{name: "lease_expired", d: &info.LeaseExpired},
+	} {
+		v, ok := values[f.name]
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid NFSv4 info %s=%s: %s", f.name, v, err)
+		}
+
+		*f.d = time.Duration(n) * time.Second
+	}
+
+	return info, nil
+}
+
+// parseNFSFSCacheStats parses a NFSFSCacheStats line using an input set of
+// integer fields. Missing fields are left 0.
+func parseNFSFSCacheStats(ss []string) (*NFSFSCacheStats, error) {
+	const fieldFscLen = 5
+	if len(ss) > fieldFscLen {
+		return nil, fmt.Errorf("invalid NFS FS-Cache stats: %v", ss)
+	}
+
+	ns := make([]uint64, fieldFscLen)
+	for i, s := range ss {
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		ns[i] = n
+	}
+
+	return &NFSFSCacheStats{
+		PagesReadOK:     ns[0],
+		PagesReadFail:   ns[1],
+		PagesWrittenOK:  ns[2],
+		PagesNotWritten: ns[3],
+		PagesUncached:   ns[4],
+	}, nil
+}
+
 // parseNFSBytesStats parses a NFSBytesStats line using an input set of
 // integer fields.
 func parseNFSBytesStats(ss []string) (*NFSBytesStats, error) {
@@ -474,8 +751,11 @@ func parseNFSEventsStats(ss []string) (*NFSEventsStats, error) {
 // line is reached.
 func parseNFSOperationStats(s *bufio.Scanner) ([]NFSOperationStats, error) {
 	const (
-		// Number of expected fields in each per-operation statistics set
-		numFields = 9
+		// Minimum number of expected fields in each per-operation statistics
+		// set. Kernel 5.3 and later add the number of errors as a tenth
+		// field.
+		minFields = 9
+		maxFields = 10
 	)
 
 	var ops []NFSOperationStats
@@ -488,19 +768,21 @@ func parseNFSOperationStats(s *bufio.Scanner) ([]NFSOperationStats, error) {
 			break
 		}
 
-		if len(ss) != numFields {
+		if len(ss) < minFields || len(ss) > maxFields {
 			return nil, fmt.Errorf("invalid NFS per-operations stats: %v", ss)
 		}
 
-		// Skip string operation name for integers
-		ns := make([]uint64, 0, numFields-1)
-		for _, st := range ss[1:] {
+		// Skip string operation name for integers. The slice is allocated for
+		// the maximum number of fields so that the errors are 0 when they are
+		// not present.
+		ns := make([]uint64, maxFields-1)
+		for i, st := range ss[1:] {
 			n, err := strconv.ParseUint(st, 10, 64)
 			if err != nil {
 				return nil, err
 			}
 
-			ns = append(ns, n)
+			ns[i] = n
 		}
 
 		ops = append(ops, NFSOperationStats{
@@ -513,6 +795,7 @@ func parseNFSOperationStats(s *bufio.Scanner) ([]NFSOperationStats, error) {
 			CumulativeQueueTime:         time.Duration(ns[5]) * time.Millisecond,
 			CumulativeTotalResponseTime: time.Duration(ns[6]) * time.Millisecond,
 			CumulativeTotalRequestTime:  time.Duration(ns[7]) * time.Millisecond,
+			Errors:                      ns[8],
 		})
 	}
 
@@ -147,6 +147,127 @@ func TestMountStats(t *testing.T) {
 				},
 			}},
 		},
+		{
+			name:    "NFSv4 device with bad mount options",
+			s:       "device 192.168.1.1:/srv mounted on /mnt/nfs with fstype nfs4 statvers=1.1\n\topts:\trw,rsize=foo",
+			invalid: true,
+		},
+		{
+			name: "NFSv3 soft krb5p mount without security flavor option OK",
+			s:    "device 192.168.1.1:/srv mounted on /mnt/nfs with fstype nfs statvers=1.1\n\topts:\tro,vers=3,soft,proto=udp,timeo=11,retrans=3\n\tcaps:\tcaps=0x3fc7,wtmult=4096\n\tsec:\tflavor=6,pseudoflavor=390005\n",
+			mounts: []*Mount{{
+				Device: "192.168.1.1:/srv",
+				Mount:  "/mnt/nfs",
+				Type:   "nfs",
+				Stats: &MountStatsNFS{
+					StatVersion: "1.1",
+					Options: map[string]string{
+						"ro":      "",
+						"vers":    "3",
+						"soft":    "",
+						"proto":   "udp",
+						"timeo":   "11",
+						"retrans": "3",
+					},
+					Version:         "3",
+					Protocol:        "udp",
+					Timeout:         1100 * time.Millisecond,
+					Retransmissions: 3,
+					Soft:            true,
+					SecurityFlavor:  "krb5p",
+					Capabilities:    0x3fc7,
+				},
+			}},
+		},
+		{
+			name:    "NFSv4 device with bad nfsv4 info",
+			s:       "device 192.168.1.1:/srv mounted on /mnt/nfs with fstype nfs4 statvers=1.1\n\tnfsv4:\tbm0=foo",
+			invalid: true,
+		},
+		{
+			name:    "NFSv4 device with bad FS-Cache stats",
+			s:       "device 192.168.1.1:/srv mounted on /mnt/nfs with fstype nfs4 statvers=1.1\n\tfsc:\t1 2 3 4 5 6",
+			invalid: true,
+		},
+		{
+			name: "NFSv4.1 device with nfsv4 info and FS-Cache stats OK",
+			s:    "device 192.168.1.1:/srv mounted on /mnt/nfs with fstype nfs4 statvers=1.1\n\topts:\trw,vers=4.1,fsc\n\tnfsv4:\tbm0=0xfdffbfff,bm1=0x40f9be3e,bm2=0x803,acl=0x3,sessions,pnfs=LAYOUT_NFSV4_1_FILES,lease_time=90,lease_expired=0\n\tfsc:\t120 3 117 1 8\n",
+			mounts: []*Mount{{
+				Device: "192.168.1.1:/srv",
+				Mount:  "/mnt/nfs",
+				Type:   "nfs4",
+				Stats: &MountStatsNFS{
+					StatVersion: "1.1",
+					Options: map[string]string{
+						"rw":   "",
+						"vers": "4.1",
+						"fsc":  "",
+					},
+					Version: "4.1",
+					NFSv4: &NFSv4Info{
+						AttributeBitmask: [3]uint64{0xfdffbfff, 0x40f9be3e, 0x803},
+						ACL:              0x3,
+						Sessions:         true,
+						PNFS:             "LAYOUT_NFSV4_1_FILES",
+						LeaseTime:        90 * time.Second,
+					},
+					FSCache: &NFSFSCacheStats{
+						PagesReadOK:     120,
+						PagesReadFail:   3,
+						PagesWrittenOK:  117,
+						PagesNotWritten: 1,
+						PagesUncached:   8,
+					},
+				},
+			}},
+		},
+		{
+			name: "NFSv4 device with empty FS-Cache stats OK",
+			s:    "device 192.168.1.1:/srv mounted on /mnt/nfs with fstype nfs4 statvers=1.1\n\tfsc:\n",
+			mounts: []*Mount{{
+				Device: "192.168.1.1:/srv",
+				Mount:  "/mnt/nfs",
+				Type:   "nfs4",
+				Stats: &MountStatsNFS{
+					StatVersion: "1.1",
+					FSCache:     &NFSFSCacheStats{},
+				},
+			}},
+		},
+		{
+			name:    "NFSv4 device with too many per-op stats fields",
+			s:       "device 192.168.1.1:/srv mounted on /mnt/nfs with fstype nfs4 statvers=1.1\nper-op statistics\n\tREAD: 0 0 0 0 0 0 0 0 0 0",
+			invalid: true,
+		},
+		{
+			name: "NFSv4 device with per-op stats errors OK",
+			s:    "device 192.168.1.1:/srv mounted on /mnt/nfs with fstype nfs4 statvers=1.1\nper-op statistics\n\tNULL: 0 0 0 0 0 0 0 0\n\tGETATTR: 200 201 1 28800 45600 3 150 160 4\n",
+			mounts: []*Mount{{
+				Device: "192.168.1.1:/srv",
+				Mount:  "/mnt/nfs",
+				Type:   "nfs4",
+				Stats: &MountStatsNFS{
+					StatVersion: "1.1",
+					Operations: []NFSOperationStats{
+						{
+							Operation: "NULL",
+						},
+						{
+							Operation:                   "GETATTR",
+							Requests:                    200,
+							Transmissions:               201,
+							MajorTimeouts:               1,
+							BytesSent:                   28800,
+							BytesReceived:               45600,
+							CumulativeQueueTime:         3 * time.Millisecond,
+							CumulativeTotalResponseTime: 150 * time.Millisecond,
+							CumulativeTotalRequestTime:  160 * time.Millisecond,
+							Errors:                      4,
+						},
+					},
+				},
+			}},
+		},
 		{
 			name: "fixtures OK",
 			mounts: []*Mount{
@@ -177,6 +298,37 @@ func TestMountStats(t *testing.T) {
 					Stats: &MountStatsNFS{
 						StatVersion: "1.1",
 						Age:         13968 * time.Second,
+						Options: map[string]string{
+							"rw":         "",
+							"vers":       "4.0",
+							"rsize":      "1048576",
+							"wsize":      "1048576",
+							"namlen":     "255",
+							"acregmin":   "3",
+							"acregmax":   "60",
+							"acdirmin":   "30",
+							"acdirmax":   "60",
+							"hard":       "",
+							"proto":      "tcp",
+							"port":       "0",
+							"timeo":      "600",
+							"retrans":    "2",
+							"sec":        "sys",
+							"clientaddr": "192.168.1.5",
+							"local_lock": "none",
+						},
+						Version:         "4.0",
+						Protocol:        "tcp",
+						ReadSize:        1048576,
+						WriteSize:       1048576,
+						Timeout:         60 * time.Second,
+						Retransmissions: 2,
+						SecurityFlavor:  "sys",
+						Capabilities:    0xfff7,
+						NFSv4: &NFSv4Info{
+							AttributeBitmask: [3]uint64{0xfdffafff, 0xf9be3e, 0},
+							PNFS:             "not configured",
+						},
 						Bytes: NFSBytesStats{
 							Read:      1207640230,
 							ReadTotal: 1210214218,
@@ -258,6 +410,24 @@ func TestMountStats(t *testing.T) {
 	}
 }
 
+func TestMountStatsNFSErrorRates(t *testing.T) {
+	stats := MountStatsNFS{
+		Operations: []NFSOperationStats{
+			{Operation: "NULL"},
+			{Operation: "READ", Requests: 1298},
+			{Operation: "GETATTR", Requests: 200, Errors: 4},
+		},
+	}
+
+	want := map[string]float64{
+		"READ":    0,
+		"GETATTR": 0.02,
+	}
+	if have := stats.ErrorRates(); !reflect.DeepEqual(want, have) {
+		t.Errorf("want error rates %v, have %v", want, have)
+	}
+}
+
 func mountsStr(mounts []*Mount) string {
 	var out string
 	for i, m := range mounts {
@@ -0,0 +1,363 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// SocketState is the state of a socket as reported in the st column of
+// /proc/net/{tcp,tcp6,udp,udp6}. UDP sockets reuse the TCP state values.
+type SocketState uint8
+
+// Socket states, see include/net/tcp_states.h in the kernel sources.
+const (
+	SocketEstablished SocketState = iota + 1
+	SocketSynSent
+	SocketSynRecv
+	SocketFinWait1
+	SocketFinWait2
+	SocketTimeWait
+	SocketClose
+	SocketCloseWait
+	SocketLastAck
+	SocketListen
+	SocketClosing
+	SocketNewSynRecv
+)
+
+var socketStateNames = map[SocketState]string{
+	SocketEstablished: "ESTABLISHED",
+	SocketSynSent:     "SYN_SENT",
+	SocketSynRecv:     "SYN_RECV",
+	SocketFinWait1:    "FIN_WAIT1",
+	SocketFinWait2:    "FIN_WAIT2",
+	SocketTimeWait:    "TIME_WAIT",
+	SocketClose:       "CLOSE",
+	SocketCloseWait:   "CLOSE_WAIT",
+	SocketLastAck:     "LAST_ACK",
+	SocketListen:      "LISTEN",
+	SocketClosing:     "CLOSING",
+	SocketNewSynRecv:  "NEW_SYN_RECV",
+}
+
+func (s SocketState) String() string {
+	if name, ok := socketStateNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("UNKNOWN(%d)", uint8(s))
+}
+
+// NetSocket is a single line parsed from /proc/net/{tcp,tcp6,udp,udp6} or
+// the same files below /proc/[pid]/net.
+type NetSocket struct {
+	// The kernel hash slot of the socket.
+	Slot uint64
+	// The local address and port.
+	LocalAddress net.IP
+	LocalPort    uint16
+	// The remote address and port. Both are unspecified for sockets which
+	// are not connected.
+	RemoteAddress net.IP
+	RemotePort    uint16
+	// The state of the socket.
+	State SocketState
+	// Bytes in the transmit queue. For TCP, data not yet acknowledged by
+	// the remote.
+	TxQueue uint64
+	// Bytes in the receive queue.
+	RxQueue uint64
+	// The kind of timer which is pending on the socket: 0 for none, 1 for
+	// the retransmit timer, 2 for the keepalive timer, 3 for the TIME_WAIT
+	// timer and 4 for the zero window probe timer.
+	TimerActive uint8
+	// Jiffies until the pending timer expires.
+	TimerExpires uint64
+	// Number of unrecovered RTO timeouts.
+	Retransmits uint64
+	// The effective UID of the socket's creator.
+	UID uint32
+	// Number of unanswered zero window probes.
+	Timeout uint64
+	// The inode of the socket, which matches the socket:[inode] link
+	// targets in /proc/[pid]/fd.
+	Inode uint64
+	// Number of datagrams dropped. Only available for UDP sockets.
+	Drops uint64
+}
+
+// Listening returns whether the socket accepts new connections or datagrams
+// on its local port. TCP sockets listen in the LISTEN state; UDP sockets
+// have no such state, so unconnected UDP sockets are considered listening.
+func (s NetSocket) Listening() bool {
+	return s.State == SocketListen || (s.State == SocketClose && s.RemotePort == 0)
+}
+
+// NetSockets is a list of sockets parsed from one of the socket tables.
+type NetSockets []NetSocket
+
+// StateCounts returns the number of sockets in each state.
+func (ns NetSockets) StateCounts() map[SocketState]uint64 {
+	counts := map[SocketState]uint64{}
+	for _, s := range ns {
+		counts[s.State]++
+	}
+	return counts
+}
+
+// ListeningPortCounts returns the number of listening sockets per local
+// port. More than one socket may listen on the same port, e.g. when bound
+// to different addresses or using SO_REUSEPORT.
+func (ns NetSockets) ListeningPortCounts() map[uint16]uint64 {
+	counts := map[uint16]uint64{}
+	for _, s := range ns {
+		if s.Listening() {
+			counts[s.LocalPort]++
+		}
+	}
+	return counts
+}
+
+// NewNetTCP returns the IPv4 TCP sockets read from /proc/net/tcp.
+func NewNetTCP() (NetSockets, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewNetTCP()
+}
+
+// NewNetTCP6 returns the IPv6 TCP sockets read from /proc/net/tcp6.
+func NewNetTCP6() (NetSockets, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewNetTCP6()
+}
+
+// NewNetUDP returns the IPv4 UDP sockets read from /proc/net/udp.
+func NewNetUDP() (NetSockets, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewNetUDP()
+}
+
+// NewNetUDP6 returns the IPv6 UDP sockets read from /proc/net/udp6.
+func NewNetUDP6() (NetSockets, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewNetUDP6()
+}
+
+// NewNetTCP returns the IPv4 TCP sockets read from /proc/net/tcp.
+func (fs FS) NewNetTCP() (NetSockets, error) {
+	return newNetSockets(fs.Path("net/tcp"))
+}
+
+// NewNetTCP6 returns the IPv6 TCP sockets read from /proc/net/tcp6.
+func (fs FS) NewNetTCP6() (NetSockets, error) {
+	return newNetSockets(fs.Path("net/tcp6"))
+}
+
+// NewNetUDP returns the IPv4 UDP sockets read from /proc/net/udp.
+func (fs FS) NewNetUDP() (NetSockets, error) {
+	return newNetSockets(fs.Path("net/udp"))
+}
+
+// NewNetUDP6 returns the IPv6 UDP sockets read from /proc/net/udp6.
+func (fs FS) NewNetUDP6() (NetSockets, error) {
+	return newNetSockets(fs.Path("net/udp6"))
+}
+
+// NewNetTCP returns the IPv4 TCP sockets of the process's network namespace
+// read from /proc/[pid]/net/tcp.
+func (p Proc) NewNetTCP() (NetSockets, error) {
+	return newNetSockets(p.path("net/tcp"))
+}
+
+// NewNetTCP6 returns the IPv6 TCP sockets of the process's network
+// namespace read from /proc/[pid]/net/tcp6.
+func (p Proc) NewNetTCP6() (NetSockets, error) {
+	return newNetSockets(p.path("net/tcp6"))
+}
+
+// NewNetUDP returns the IPv4 UDP sockets of the process's network namespace
+// read from /proc/[pid]/net/udp.
+func (p Proc) NewNetUDP() (NetSockets, error) {
+	return newNetSockets(p.path("net/udp"))
+}
+
+// NewNetUDP6 returns the IPv6 UDP sockets of the process's network
+// namespace read from /proc/[pid]/net/udp6.
+func (p Proc) NewNetUDP6() (NetSockets, error) {
+	return newNetSockets(p.path("net/udp6"))
+}
+
+// newNetSockets creates a new NetSockets from the contents of the given file.
+func newNetSockets(file string) (NetSockets, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseNetSockets(f)
+}
+
+func parseNetSockets(r io.Reader) (NetSockets, error) {
+	ns := NetSockets{}
+
+	s := bufio.NewScanner(r)
+	for n := 0; s.Scan(); n++ {
+		// Skip the header line.
+		if n == 0 {
+			continue
+		}
+
+		line, err := parseNetSocketLine(s.Text())
+		if err != nil {
+			return nil, err
+		}
+
+		ns = append(ns, *line)
+	}
+
+	return ns, s.Err()
+}
+
+// parseNetSocketLine parses a single line of a socket table. The header line
+// must be filtered prior to calling this function.
+func parseNetSocketLine(rawLine string) (*NetSocket, error) {
+	fields := strings.Fields(rawLine)
+	if len(fields) < 10 {
+		return nil, fmt.Errorf("invalid socket line, too few fields: %q", rawLine)
+	}
+
+	var (
+		line = &NetSocket{}
+		err  error
+	)
+
+	if line.Slot, err = strconv.ParseUint(strings.TrimSuffix(fields[0], ":"), 10, 64); err != nil {
+		return nil, fmt.Errorf("couldn't parse slot %q: %s", fields[0], err)
+	}
+	if line.LocalAddress, line.LocalPort, err = parseNetSocketAddr(fields[1], nativeEndian()); err != nil {
+		return nil, err
+	}
+	if line.RemoteAddress, line.RemotePort, err = parseNetSocketAddr(fields[2], nativeEndian()); err != nil {
+		return nil, err
+	}
+
+	st, err := strconv.ParseUint(fields[3], 16, 8)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse state %q: %s", fields[3], err)
+	}
+	line.State = SocketState(st)
+
+	queues := strings.Split(fields[4], ":")
+	if len(queues) != 2 {
+		return nil, fmt.Errorf("unexpected tx_queue:rx_queue format: %q", fields[4])
+	}
+	if line.TxQueue, err = strconv.ParseUint(queues[0], 16, 64); err != nil {
+		return nil, fmt.Errorf("couldn't parse tx_queue %q: %s", queues[0], err)
+	}
+	if line.RxQueue, err = strconv.ParseUint(queues[1], 16, 64); err != nil {
+		return nil, fmt.Errorf("couldn't parse rx_queue %q: %s", queues[1], err)
+	}
+
+	timer := strings.Split(fields[5], ":")
+	if len(timer) != 2 {
+		return nil, fmt.Errorf("unexpected tr:tm->when format: %q", fields[5])
+	}
+	tr, err := strconv.ParseUint(timer[0], 16, 8)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse timer %q: %s", timer[0], err)
+	}
+	line.TimerActive = uint8(tr)
+	if line.TimerExpires, err = strconv.ParseUint(timer[1], 16, 64); err != nil {
+		return nil, fmt.Errorf("couldn't parse timer expiry %q: %s", timer[1], err)
+	}
+
+	if line.Retransmits, err = strconv.ParseUint(fields[6], 16, 64); err != nil {
+		return nil, fmt.Errorf("couldn't parse retransmits %q: %s", fields[6], err)
+	}
+	uid, err := strconv.ParseUint(fields[7], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse uid %q: %s", fields[7], err)
+	}
+	line.UID = uint32(uid)
+	if line.Timeout, err = strconv.ParseUint(fields[8], 10, 64); err != nil {
+		return nil, fmt.Errorf("couldn't parse timeout %q: %s", fields[8], err)
+	}
+	if line.Inode, err = strconv.ParseUint(fields[9], 10, 64); err != nil {
+		return nil, fmt.Errorf("couldn't parse inode %q: %s", fields[9], err)
+	}
+
+	// UDP sockets end with "ref pointer drops".
+	if len(fields) == 13 {
+		if line.Drops, err = strconv.ParseUint(fields[12], 10, 64); err != nil {
+			return nil, fmt.Errorf("couldn't parse drops %q: %s", fields[12], err)
+		}
+	}
+
+	return line, nil
+}
+
+// parseNetSocketAddr parses an address in the hex ADDR:PORT format of the
+// socket tables.
+//
+// Unlike the addresses in /proc/net/ip_vs, which parseIPPort decodes, the
+// address is printed as 32-bit words in the byte order of the kernel, which
+// is given by order.
+func parseNetSocketAddr(s string, order binary.ByteOrder) (net.IP, uint16, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return nil, 0, fmt.Errorf("unexpected address format: %q", s)
+	}
+
+	ip, err := hex.DecodeString(parts[0])
+	if err != nil {
+		return nil, 0, fmt.Errorf("couldn't parse address %q: %s", parts[0], err)
+	}
+	if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+		return nil, 0, fmt.Errorf("unexpected address length: %q", parts[0])
+	}
+	for i := 0; i < len(ip); i += 4 {
+		order.PutUint32(ip[i:], binary.BigEndian.Uint32(ip[i:]))
+	}
+
+	port, err := strconv.ParseUint(parts[1], 16, 16)
+	if err != nil {
+		return nil, 0, fmt.Errorf("couldn't parse port %q: %s", parts[1], err)
+	}
+
+	return net.IP(ip), uint16(port), nil
+}
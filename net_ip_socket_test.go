@@ -0,0 +1,212 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"encoding/binary"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestNetSocketParseLine(t *testing.T) {
+	const rawLine = `   2: 0F02000A:0016 0202000A:C9A4 01 00000024:00000010 01:00000019 00000003     0        0 23312 4 0000000000000000 20 4 29 10 -1`
+
+	have, err := parseNetSocketLine(rawLine)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &NetSocket{
+		Slot:          2,
+		LocalAddress:  net.IP{10, 0, 2, 15},
+		LocalPort:     22,
+		RemoteAddress: net.IP{10, 0, 2, 2},
+		RemotePort:    51620,
+		State:         SocketEstablished,
+		TxQueue:       36,
+		RxQueue:       16,
+		TimerActive:   1,
+		TimerExpires:  25,
+		Retransmits:   3,
+		Inode:         23312,
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("want %+v, have %+v", want, have)
+	}
+}
+
+func TestNetSocketParseLineInvalid(t *testing.T) {
+	for _, rawLine := range []string{
+		`0: 0100007F:0CEA 00000000:0000 0A 00000000:00000000 00:00000000 00000000`,
+		`0: 0100007F 00000000:0000 0A 00000000:00000000 00:00000000 00000000 118 0 19547`,
+		`0: 0100007F0:0CEA 00000000:0000 0A 00000000:00000000 00:00000000 00000000 118 0 19547`,
+		`0: 0100007F:0CEA 00000000:0000 0A 00000000 00:00000000 00000000 118 0 19547`,
+		`0: 0100007F:0CEA 00000000:0000 0A 00000000:00000000 00:00000000 00000000 x 0 19547`,
+	} {
+		if _, err := parseNetSocketLine(rawLine); err == nil {
+			t.Errorf("want error parsing %q, have none", rawLine)
+		}
+	}
+}
+
+func TestParseNetSocketAddr(t *testing.T) {
+	for _, tt := range []struct {
+		addr  string
+		order binary.ByteOrder
+		ip    net.IP
+		port  uint16
+	}{
+		{
+			addr:  "0F02000A:0016",
+			order: binary.LittleEndian,
+			ip:    net.IP{10, 0, 2, 15},
+			port:  22,
+		},
+		{
+			addr:  "0A00020F:0016",
+			order: binary.BigEndian,
+			ip:    net.IP{10, 0, 2, 15},
+			port:  22,
+		},
+		{
+			addr:  "B80D01200000000067452301EFCDAB89:01BB",
+			order: binary.LittleEndian,
+			ip:    net.ParseIP("2001:db8::123:4567:89ab:cdef"),
+			port:  443,
+		},
+		{
+			addr:  "20010DB8000000000123456789ABCDEF:01BB",
+			order: binary.BigEndian,
+			ip:    net.ParseIP("2001:db8::123:4567:89ab:cdef"),
+			port:  443,
+		},
+	} {
+		ip, port, err := parseNetSocketAddr(tt.addr, tt.order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !tt.ip.Equal(ip) || tt.port != port {
+			t.Errorf("%s (%s): want %s:%d, have %s:%d", tt.addr, tt.order, tt.ip, tt.port, ip, port)
+		}
+	}
+}
+
+func TestNewNetTCP(t *testing.T) {
+	ns, err := FS("fixtures").NewNetTCP()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 4, len(ns); want != have {
+		t.Fatalf("want %d sockets, have %d", want, have)
+	}
+	if want, have := net.IPv4(127, 0, 0, 1), ns[0].LocalAddress; !want.Equal(have) {
+		t.Errorf("want local address %s, have %s", want, have)
+	}
+	if want, have := uint32(118), ns[0].UID; want != have {
+		t.Errorf("want uid %d, have %d", want, have)
+	}
+	if want, have := uint64(2), ns[1].RxQueue; want != have {
+		t.Errorf("want rx_queue %d, have %d", want, have)
+	}
+	if want, have := SocketTimeWait, ns[3].State; want != have {
+		t.Errorf("want state %s, have %s", want, have)
+	}
+
+	states := map[SocketState]uint64{SocketListen: 2, SocketEstablished: 1, SocketTimeWait: 1}
+	if want, have := states, ns.StateCounts(); !reflect.DeepEqual(want, have) {
+		t.Errorf("want state counts %v, have %v", want, have)
+	}
+	ports := map[uint16]uint64{3306: 1, 22: 1}
+	if want, have := ports, ns.ListeningPortCounts(); !reflect.DeepEqual(want, have) {
+		t.Errorf("want listening port counts %v, have %v", want, have)
+	}
+}
+
+func TestNewNetTCP6(t *testing.T) {
+	ns, err := FS("fixtures").NewNetTCP6()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 3, len(ns); want != have {
+		t.Fatalf("want %d sockets, have %d", want, have)
+	}
+	for i, want := range []net.IP{net.IPv6zero, net.IPv6loopback, net.IPv4(10, 0, 2, 15)} {
+		if have := ns[i].LocalAddress; !want.Equal(have) {
+			t.Errorf("want local address %s, have %s", want, have)
+		}
+	}
+	if want, have := uint16(55520), ns[2].RemotePort; want != have {
+		t.Errorf("want remote port %d, have %d", want, have)
+	}
+
+	ports := map[uint16]uint64{22: 1, 8080: 1}
+	if want, have := ports, ns.ListeningPortCounts(); !reflect.DeepEqual(want, have) {
+		t.Errorf("want listening port counts %v, have %v", want, have)
+	}
+}
+
+func TestNewNetUDP(t *testing.T) {
+	ns, err := FS("fixtures").NewNetUDP()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 3, len(ns); want != have {
+		t.Fatalf("want %d sockets, have %d", want, have)
+	}
+	if want, have := net.IPv4(127, 0, 0, 53), ns[0].LocalAddress; !want.Equal(have) {
+		t.Errorf("want local address %s, have %s", want, have)
+	}
+	if want, have := uint64(12), ns[2].Drops; want != have {
+		t.Errorf("want drops %d, have %d", want, have)
+	}
+
+	ports := map[uint16]uint64{53: 1, 224: 1}
+	if want, have := ports, ns.ListeningPortCounts(); !reflect.DeepEqual(want, have) {
+		t.Errorf("want listening port counts %v, have %v", want, have)
+	}
+
+	ns6, err := FS("fixtures").NewNetUDP6()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := uint64(16511), ns6[0].Inode; want != have {
+		t.Errorf("want inode %d, have %d", want, have)
+	}
+}
+
+func TestProcNewNetTCP(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ns, err := p.NewNetTCP()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 2, len(ns); want != have {
+		t.Fatalf("want %d sockets, have %d", want, have)
+	}
+	if want, have := net.IPv4(10, 17, 0, 2), ns[1].LocalAddress; !want.Equal(have) {
+		t.Errorf("want local address %s, have %s", want, have)
+	}
+	if want, have := uint16(8080), ns[1].LocalPort; want != have {
+		t.Errorf("want local port %d, have %d", want, have)
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// NetSNMP holds the IP, ICMP, TCP and UDP counters read from /proc/net/snmp
+// or /proc/[pid]/net/snmp. The outer map is keyed by protocol (e.g. "Tcp"),
+// the inner maps by counter name (e.g. "RetransSegs").
+type NetSNMP map[string]map[string]int64
+
+// Netstat holds the extended counters read from /proc/net/netstat or
+// /proc/[pid]/net/netstat. The outer map is keyed by protocol (e.g.
+// "TcpExt"), the inner maps by counter name (e.g. "ListenOverflows").
+type Netstat map[string]map[string]int64
+
+// NewNetSNMP returns the protocol counters read from /proc/net/snmp.
+func NewNetSNMP() (NetSNMP, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewNetSNMP()
+}
+
+// NewNetSNMP returns the protocol counters read from /proc/net/snmp.
+func (fs FS) NewNetSNMP() (NetSNMP, error) {
+	return newNetProtocolCounters(fs.Path("net/snmp"))
+}
+
+// NewNetSNMP returns the protocol counters of the process's network
+// namespace read from /proc/[pid]/net/snmp.
+func (p Proc) NewNetSNMP() (NetSNMP, error) {
+	return newNetProtocolCounters(p.path("net/snmp"))
+}
+
+// NewNetstat returns the extended protocol counters read from
+// /proc/net/netstat.
+func NewNetstat() (Netstat, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewNetstat()
+}
+
+// NewNetstat returns the extended protocol counters read from
+// /proc/net/netstat.
+func (fs FS) NewNetstat() (Netstat, error) {
+	return newNetProtocolCounters(fs.Path("net/netstat"))
+}
+
+// NewNetstat returns the extended protocol counters of the process's network
+// namespace read from /proc/[pid]/net/netstat.
+func (p Proc) NewNetstat() (Netstat, error) {
+	return newNetProtocolCounters(p.path("net/netstat"))
+}
+
+// TCPActiveOpens returns the number of active TCP connection openings.
+func (s NetSNMP) TCPActiveOpens() int64 { return s["Tcp"]["ActiveOpens"] }
+
+// TCPPassiveOpens returns the number of passive TCP connection openings.
+func (s NetSNMP) TCPPassiveOpens() int64 { return s["Tcp"]["PassiveOpens"] }
+
+// TCPCurrEstab returns the number of TCP connections currently in the
+// ESTABLISHED or CLOSE-WAIT state.
+func (s NetSNMP) TCPCurrEstab() int64 { return s["Tcp"]["CurrEstab"] }
+
+// TCPRetransSegs returns the number of retransmitted TCP segments.
+func (s NetSNMP) TCPRetransSegs() int64 { return s["Tcp"]["RetransSegs"] }
+
+// TCPInErrs returns the number of TCP segments received in error.
+func (s NetSNMP) TCPInErrs() int64 { return s["Tcp"]["InErrs"] }
+
+// TCPOutRsts returns the number of TCP segments sent with the RST flag.
+func (s NetSNMP) TCPOutRsts() int64 { return s["Tcp"]["OutRsts"] }
+
+// UDPInDatagrams returns the number of UDP datagrams delivered to users.
+func (s NetSNMP) UDPInDatagrams() int64 { return s["Udp"]["InDatagrams"] }
+
+// UDPOutDatagrams returns the number of UDP datagrams sent.
+func (s NetSNMP) UDPOutDatagrams() int64 { return s["Udp"]["OutDatagrams"] }
+
+// UDPNoPorts returns the number of UDP datagrams received for a port
+// without a listener.
+func (s NetSNMP) UDPNoPorts() int64 { return s["Udp"]["NoPorts"] }
+
+// UDPInErrors returns the number of UDP datagrams which could not be
+// delivered for reasons other than a missing listener.
+func (s NetSNMP) UDPInErrors() int64 { return s["Udp"]["InErrors"] }
+
+// UDPRcvbufErrors returns the number of UDP datagrams dropped because the
+// receive buffer was full.
+func (s NetSNMP) UDPRcvbufErrors() int64 { return s["Udp"]["RcvbufErrors"] }
+
+// UDPSndbufErrors returns the number of UDP datagrams dropped because the
+// send buffer was full.
+func (s NetSNMP) UDPSndbufErrors() int64 { return s["Udp"]["SndbufErrors"] }
+
+// IPInReceives returns the number of IP datagrams received.
+func (s NetSNMP) IPInReceives() int64 { return s["Ip"]["InReceives"] }
+
+// IPInDiscards returns the number of received IP datagrams discarded
+// without errors, e.g. for lack of buffer space.
+func (s NetSNMP) IPInDiscards() int64 { return s["Ip"]["InDiscards"] }
+
+// IPForwDatagrams returns the number of forwarded IP datagrams.
+func (s NetSNMP) IPForwDatagrams() int64 { return s["Ip"]["ForwDatagrams"] }
+
+// ICMPInErrors returns the number of ICMP messages received in error.
+func (s NetSNMP) ICMPInErrors() int64 { return s["Icmp"]["InErrors"] }
+
+// ListenOverflows returns the number of times the accept queue of a
+// listening TCP socket overflowed.
+func (s Netstat) ListenOverflows() int64 { return s["TcpExt"]["ListenOverflows"] }
+
+// ListenDrops returns the number of SYNs to listening TCP sockets which
+// were dropped.
+func (s Netstat) ListenDrops() int64 { return s["TcpExt"]["ListenDrops"] }
+
+// SyncookiesSent returns the number of SYN cookies sent.
+func (s Netstat) SyncookiesSent() int64 { return s["TcpExt"]["SyncookiesSent"] }
+
+// SyncookiesRecv returns the number of valid SYN cookies received.
+func (s Netstat) SyncookiesRecv() int64 { return s["TcpExt"]["SyncookiesRecv"] }
+
+// SyncookiesFailed returns the number of invalid SYN cookies received.
+func (s Netstat) SyncookiesFailed() int64 { return s["TcpExt"]["SyncookiesFailed"] }
+
+// TCPTimeouts returns the number of TCP retransmission timeouts.
+func (s Netstat) TCPTimeouts() int64 { return s["TcpExt"]["TCPTimeouts"] }
+
+// TCPLostRetransmit returns the number of TCP retransmissions which were
+// lost again.
+func (s Netstat) TCPLostRetransmit() int64 { return s["TcpExt"]["TCPLostRetransmit"] }
+
+// TCPBacklogDrop returns the number of TCP packets dropped because the
+// socket backlog was full.
+func (s Netstat) TCPBacklogDrop() int64 { return s["TcpExt"]["TCPBacklogDrop"] }
+
+// IPInOctets returns the number of bytes received by IP.
+func (s Netstat) IPInOctets() int64 { return s["IpExt"]["InOctets"] }
+
+// IPOutOctets returns the number of bytes sent by IP.
+func (s Netstat) IPOutOctets() int64 { return s["IpExt"]["OutOctets"] }
+
+// newNetProtocolCounters reads a file in the format of /proc/net/snmp and
+// /proc/net/netstat.
+func newNetProtocolCounters(file string) (map[string]map[string]int64, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	counters, err := parseNetProtocolCounters(f)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %s", f.Name(), err)
+	}
+
+	return counters, nil
+}
+
+// parseNetProtocolCounters parses pairs of lines, where the first line of a
+// pair names the counters and the second holds their values, both prefixed
+// with the protocol:
+//
+//	Tcp: RtoAlgorithm RtoMin ...
+//	Tcp: 1 200 ...
+func parseNetProtocolCounters(r io.Reader) (map[string]map[string]int64, error) {
+	counters := map[string]map[string]int64{}
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		names := strings.Fields(s.Text())
+		if len(names) == 0 {
+			continue
+		}
+		if !s.Scan() {
+			return nil, fmt.Errorf("missing values for %s", names[0])
+		}
+		values := strings.Fields(s.Text())
+
+		if len(values) == 0 || names[0] != values[0] {
+			return nil, fmt.Errorf("mismatched protocols in header %q and values %q", names[0], s.Text())
+		}
+		if len(names) != len(values) {
+			return nil, fmt.Errorf("mismatched number of counters for %s: %d names, %d values", names[0], len(names)-1, len(values)-1)
+		}
+
+		proto := strings.TrimSuffix(names[0], ":")
+		if counters[proto] == nil {
+			counters[proto] = make(map[string]int64, len(names)-1)
+		}
+		for i := 1; i < len(names); i++ {
+			v, err := strconv.ParseInt(values[i], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for %s %s: %s", proto, names[i], err)
+			}
+			counters[proto][names[i]] = v
+		}
+	}
+
+	return counters, s.Err()
+}
@@ -0,0 +1,127 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// NetSNMP6 holds the IPv6, ICMPv6 and UDP over IPv6 counters read from
+// /proc/net/snmp6 or /proc/[pid]/net/snmp6. The outer map is keyed by
+// protocol (e.g. "Udp6"), the inner maps by counter name without the
+// protocol prefix (e.g. "RcvbufErrors").
+type NetSNMP6 map[string]map[string]int64
+
+// NewNetSNMP6 returns the IPv6 protocol counters read from /proc/net/snmp6.
+func NewNetSNMP6() (NetSNMP6, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewNetSNMP6()
+}
+
+// NewNetSNMP6 returns the IPv6 protocol counters read from /proc/net/snmp6.
+func (fs FS) NewNetSNMP6() (NetSNMP6, error) {
+	return newNetSNMP6(fs.Path("net/snmp6"))
+}
+
+// NewNetSNMP6 returns the IPv6 protocol counters of the process's network
+// namespace read from /proc/[pid]/net/snmp6.
+func (p Proc) NewNetSNMP6() (NetSNMP6, error) {
+	return newNetSNMP6(p.path("net/snmp6"))
+}
+
+// IP6InReceives returns the number of IPv6 datagrams received.
+func (s NetSNMP6) IP6InReceives() int64 { return s["Ip6"]["InReceives"] }
+
+// IP6InDiscards returns the number of received IPv6 datagrams discarded
+// without errors, e.g. for lack of buffer space.
+func (s NetSNMP6) IP6InDiscards() int64 { return s["Ip6"]["InDiscards"] }
+
+// ICMP6InErrors returns the number of ICMPv6 messages received in error.
+func (s NetSNMP6) ICMP6InErrors() int64 { return s["Icmp6"]["InErrors"] }
+
+// UDP6InDatagrams returns the number of UDP over IPv6 datagrams delivered
+// to users.
+func (s NetSNMP6) UDP6InDatagrams() int64 { return s["Udp6"]["InDatagrams"] }
+
+// UDP6InErrors returns the number of UDP over IPv6 datagrams which could
+// not be delivered for reasons other than a missing listener.
+func (s NetSNMP6) UDP6InErrors() int64 { return s["Udp6"]["InErrors"] }
+
+// UDP6RcvbufErrors returns the number of UDP over IPv6 datagrams dropped
+// because the receive buffer was full.
+func (s NetSNMP6) UDP6RcvbufErrors() int64 { return s["Udp6"]["RcvbufErrors"] }
+
+// newNetSNMP6 creates a new NetSNMP6 from the contents of the given file.
+func newNetSNMP6(file string) (NetSNMP6, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	counters, err := parseNetSNMP6(f)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %s", f.Name(), err)
+	}
+
+	return counters, nil
+}
+
+// parseNetSNMP6 parses lines of a counter name, prefixed with its protocol,
+// and a value:
+//
+//	Udp6InDatagrams    1243
+func parseNetSNMP6(r io.Reader) (NetSNMP6, error) {
+	counters := NetSNMP6{}
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("malformed snmp6 line: %q", s.Text())
+		}
+
+		// Protocols are Ip6, Icmp6, Udp6 and UdpLite6, so the counter name
+		// starts after the first "6".
+		i := strings.Index(fields[0], "6")
+		if i < 1 || i == len(fields[0])-1 {
+			return nil, fmt.Errorf("unexpected snmp6 counter name: %q", fields[0])
+		}
+		proto, name := fields[0][:i+1], fields[0][i+1:]
+
+		v, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %s", fields[0], err)
+		}
+
+		if counters[proto] == nil {
+			counters[proto] = map[string]int64{}
+		}
+		counters[proto][name] = v
+	}
+
+	return counters, s.Err()
+}
@@ -0,0 +1,75 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNetSNMP6(t *testing.T) {
+	s, err := FS("fixtures").NewNetSNMP6()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name string
+		want int64
+		have int64
+	}{
+		{name: "Ip6 InReceives", want: 4521, have: s.IP6InReceives()},
+		{name: "Ip6 InDiscards", want: 7, have: s.IP6InDiscards()},
+		{name: "Icmp6 InErrors", want: 1, have: s.ICMP6InErrors()},
+		{name: "Icmp6 InType133", want: 4, have: s["Icmp6"]["InType133"]},
+		{name: "Udp6 InErrors", want: 21, have: s.UDP6InErrors()},
+		{name: "Udp6 RcvbufErrors", want: 9, have: s.UDP6RcvbufErrors()},
+	} {
+		if test.want != test.have {
+			t.Errorf("want %s %d, have %d", test.name, test.want, test.have)
+		}
+	}
+
+	if _, ok := s["UdpLite6"]["InDatagrams"]; !ok {
+		t.Error("want UdpLite6 InDatagrams to be present")
+	}
+}
+
+func TestProcNetSNMP6(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := p.NewNetSNMP6()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := int64(1243), s.UDP6InDatagrams(); want != have {
+		t.Errorf("want Udp6 InDatagrams %d, have %d", want, have)
+	}
+}
+
+func TestParseNetSNMP6Invalid(t *testing.T) {
+	for _, in := range []string{
+		"Ip6InReceives\n",
+		"Ip6InReceives x\n",
+		"InReceives 1\n",
+		"Ip6 1\n",
+	} {
+		if _, err := parseNetSNMP6(strings.NewReader(in)); err == nil {
+			t.Errorf("want error parsing %q, have none", in)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNetSNMP(t *testing.T) {
+	s, err := FS("fixtures").NewNetSNMP()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name string
+		want int64
+		have int64
+	}{
+		{name: "Tcp MaxConn", want: -1, have: s["Tcp"]["MaxConn"]},
+		{name: "IcmpMsg InType3", want: 43, have: s["IcmpMsg"]["InType3"]},
+		{name: "Ip InReceives", want: 2056291, have: s.IPInReceives()},
+		{name: "Icmp InErrors", want: 2, have: s.ICMPInErrors()},
+		{name: "Tcp ActiveOpens", want: 3556, have: s.TCPActiveOpens()},
+		{name: "Tcp RetransSegs", want: 1275, have: s.TCPRetransSegs()},
+		{name: "Tcp OutRsts", want: 1104, have: s.TCPOutRsts()},
+		{name: "Udp InErrors", want: 17, have: s.UDPInErrors()},
+		{name: "Udp RcvbufErrors", want: 12, have: s.UDPRcvbufErrors()},
+	} {
+		if test.want != test.have {
+			t.Errorf("want %s %d, have %d", test.name, test.want, test.have)
+		}
+	}
+
+	if want, have := 6, len(s); want != have {
+		t.Errorf("want %d protocols, have %d", want, have)
+	}
+}
+
+func TestNetstat(t *testing.T) {
+	s, err := FS("fixtures").NewNetstat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name string
+		want int64
+		have int64
+	}{
+		{name: "TcpExt ListenOverflows", want: 512, have: s.ListenOverflows()},
+		{name: "TcpExt ListenDrops", want: 517, have: s.ListenDrops()},
+		{name: "TcpExt SyncookiesSent", want: 31, have: s.SyncookiesSent()},
+		{name: "TcpExt SyncookiesFailed", want: 4, have: s.SyncookiesFailed()},
+		{name: "TcpExt TCPFastOpenActive", want: 0, have: s["TcpExt"]["TCPFastOpenActive"]},
+		{name: "IpExt InOctets", want: 2345637891, have: s.IPInOctets()},
+	} {
+		if test.want != test.have {
+			t.Errorf("want %s %d, have %d", test.name, test.want, test.have)
+		}
+	}
+}
+
+func TestProcNetSNMP(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := p.NewNetSNMP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := int64(42), s.TCPActiveOpens(); want != have {
+		t.Errorf("want Tcp ActiveOpens %d, have %d", want, have)
+	}
+
+	ns, err := p.NewNetstat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := int64(512), ns.ListenOverflows(); want != have {
+		t.Errorf("want TcpExt ListenOverflows %d, have %d", want, have)
+	}
+}
+
+func TestParseNetProtocolCountersInvalid(t *testing.T) {
+	for _, in := range []string{
+		"Tcp: RtoAlgorithm RtoMin\n",
+		"Tcp: RtoAlgorithm RtoMin\nUdp: 1 200\n",
+		"Tcp: RtoAlgorithm RtoMin\nTcp: 1\n",
+		"Tcp: RtoAlgorithm RtoMin\nTcp: 1 x\n",
+	} {
+		if _, err := parseNetProtocolCounters(strings.NewReader(in)); err == nil {
+			t.Errorf("want error parsing %q, have none", in)
+		}
+	}
+}
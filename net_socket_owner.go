@@ -0,0 +1,173 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SocketOwner is a file descriptor of a process which refers to a socket.
+type SocketOwner struct {
+	PID int
+	FD  uintptr
+}
+
+// SocketOwners maps socket inodes to the file descriptors referring to
+// them. The inodes match NetSocket.Inode for TCP and UDP sockets, as well as
+// the inodes of Unix and other sockets.
+type SocketOwners map[uint64][]SocketOwner
+
+// socketOwnersByPID sorts socket owners by PID and file descriptor.
+type socketOwnersByPID []SocketOwner
+
+func (o socketOwnersByPID) Len() int      { return len(o) }
+func (o socketOwnersByPID) Swap(i, j int) { o[i], o[j] = o[j], o[i] }
+func (o socketOwnersByPID) Less(i, j int) bool {
+	if o[i].PID != o[j].PID {
+		return o[i].PID < o[j].PID
+	}
+	return o[i].FD < o[j].FD
+}
+
+// ProcErrors holds the errors encountered while reading a set of processes,
+// keyed by PID. It is returned alongside partial results.
+type ProcErrors map[int]error
+
+func (e ProcErrors) Error() string {
+	pids := make([]int, 0, len(e))
+	for pid := range e {
+		pids = append(pids, pid)
+	}
+	sort.Ints(pids)
+
+	msgs := make([]string, 0, len(pids))
+	for _, pid := range pids {
+		msgs = append(msgs, fmt.Sprintf("pid %d: %s", pid, e[pid]))
+	}
+
+	return fmt.Sprintf("could not read %d processes: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// SocketOwners returns the file descriptors referring to each socket, read
+// from the fd directories of all processes.
+//
+// Processes which exit during the scan are skipped. If some processes can't
+// be read, e.g. because of missing permissions, the sockets of all other
+// processes are returned along with a ProcErrors error.
+func (fs FS) SocketOwners() (SocketOwners, error) {
+	procs, err := fs.AllProcs()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		owners = SocketOwners{}
+		errs   = ProcErrors{}
+	)
+	for _, p := range procs {
+		inodes, err := p.socketInodes()
+		if err != nil {
+			if !os.IsNotExist(err) {
+				errs[p.PID] = err
+			}
+			continue
+		}
+
+		for fd, inode := range inodes {
+			owners[inode] = append(owners[inode], SocketOwner{PID: p.PID, FD: fd})
+		}
+	}
+
+	for _, o := range owners {
+		sort.Sort(socketOwnersByPID(o))
+	}
+
+	if len(errs) > 0 {
+		return owners, errs
+	}
+	return owners, nil
+}
+
+// TCPListeners returns the file descriptors of the sockets listening on the
+// given TCP port, over both IPv4 and IPv6. Partial results are returned with
+// a ProcErrors error, like for SocketOwners.
+func (fs FS) TCPListeners(port uint16) ([]SocketOwner, error) {
+	var sockets NetSockets
+	for _, file := range []string{"net/tcp", "net/tcp6"} {
+		ns, err := newNetSockets(fs.Path(file))
+		if err != nil {
+			// IPv6 may be disabled.
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		sockets = append(sockets, ns...)
+	}
+
+	owners, err := fs.SocketOwners()
+	if _, ok := err.(ProcErrors); err != nil && !ok {
+		return nil, err
+	}
+
+	listeners := []SocketOwner{}
+	for _, s := range sockets {
+		if s.State == SocketListen && s.LocalPort == port {
+			listeners = append(listeners, owners[s.Inode]...)
+		}
+	}
+
+	return listeners, err
+}
+
+// socketInodes returns the inodes of the sockets the process's file
+// descriptors refer to, keyed by file descriptor.
+func (p Proc) socketInodes() (map[uintptr]uint64, error) {
+	names, err := p.fileDescriptors()
+	if err != nil {
+		return nil, err
+	}
+
+	inodes := map[uintptr]uint64{}
+	for _, name := range names {
+		target, err := os.Readlink(p.path("fd", name))
+		if err != nil {
+			// The file descriptor was closed since listing the directory.
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if !strings.HasPrefix(target, "socket:[") {
+			continue
+		}
+
+		fd, err := strconv.ParseUint(name, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse fd %s: %s", name, err)
+		}
+		inode, err := strconv.ParseUint(strings.TrimSuffix(target[len("socket:["):], "]"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse socket inode from %q: %s", target, err)
+		}
+
+		inodes[uintptr(fd)] = inode
+	}
+
+	return inodes, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSocketOwners(t *testing.T) {
+	owners, err := FS("fixtures").SocketOwners()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := SocketOwners{
+		41001: {{PID: 26232, FD: 5}, {PID: 26234, FD: 5}},
+		41002: {{PID: 26232, FD: 6}},
+		15218: {{PID: 26234, FD: 3}},
+		15220: {{PID: 26234, FD: 4}},
+		98765: {{PID: 26234, FD: 6}},
+		31337: {{PID: 26234, FD: 8}},
+	}
+	if !reflect.DeepEqual(want, owners) {
+		t.Errorf("want socket owners %v, have %v", want, owners)
+	}
+}
+
+func TestTCPListeners(t *testing.T) {
+	for _, tt := range []struct {
+		port uint16
+		want []SocketOwner
+	}{
+		{port: 22, want: []SocketOwner{{PID: 26234, FD: 3}, {PID: 26234, FD: 4}}},
+		{port: 8080, want: []SocketOwner{{PID: 26234, FD: 8}}},
+		{port: 3306, want: []SocketOwner{}},
+	} {
+		have, err := FS("fixtures").TCPListeners(tt.port)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tt.want, have) {
+			t.Errorf("port %d: want listeners %v, have %v", tt.port, tt.want, have)
+		}
+	}
+}
+
+func TestProcErrors(t *testing.T) {
+	err := ProcErrors{
+		20: errors.New("permission denied"),
+		10: errors.New("invalid fd"),
+	}
+
+	want := "could not read 2 processes: pid 10: invalid fd; pid 20: permission denied"
+	if have := err.Error(); want != have {
+		t.Errorf("want error %q, have %q", want, have)
+	}
+}
@@ -245,6 +245,12 @@ type ClientRPCStats struct {
 	V2Stats       V2Stats
 	V3Stats       V3Stats
 	ClientV4Stats ClientV4Stats
+	// V4OpCounts maps the names of the NFSv4 client procedures of the
+	// "proc4" line, e.g. "READ" or "LAYOUTGET", to their counts.
+	V4OpCounts map[string]uint64
+	// Raw holds the values of lines which are not known to the parser,
+	// keyed by the line label.
+	Raw map[string][]uint64
 }
 
 // ServerRPCStats models all stats from /proc/net/rpc/nfsd.
@@ -260,4 +266,10 @@ type ServerRPCStats struct {
 	V3Stats        V3Stats
 	ServerV4Stats  ServerV4Stats
 	V4Ops          V4Ops
+	// V4OpCounts maps the names of the NFSv4 operations of the "proc4ops"
+	// line, e.g. "COPY" or "SEEK", to their counts.
+	V4OpCounts map[string]uint64
+	// Raw holds the values of lines which are not known to the parser,
+	// keyed by the line label.
+	Raw map[string][]uint64
 }
@@ -22,14 +22,31 @@ import (
 	"github.com/prometheus/procfs/internal/util"
 )
 
-// ParseClientRPCStats returns stats read from /proc/net/rpc/nfs
+// knownClientLines are the labels of the lines of /proc/net/rpc/nfs which
+// are modeled by ClientRPCStats.
+var knownClientLines = map[string]bool{
+	"net": true, "rpc": true, "proc2": true, "proc3": true, "proc4": true,
+}
+
+// ParseClientRPCStats returns stats read from /proc/net/rpc/nfs. Lines which
+// are not known to the parser are collected in the Raw map if all their
+// values are numeric, including lines without any value, and are skipped
+// otherwise.
 func ParseClientRPCStats(r io.Reader) (*ClientRPCStats, error) {
-	stats := &ClientRPCStats{}
+	stats := &ClientRPCStats{Raw: map[string][]uint64{}}
 
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
 		parts := strings.Fields(scanner.Text())
+		if len(parts) > 0 && !knownClientLines[parts[0]] {
+			// Lines added by newer kernels may have no or non-numeric values.
+			if values, err := util.ParseUint64s(parts[1:]); err == nil {
+				stats.Raw[parts[0]] = values
+			}
+			continue
+		}
+
 		// require at least <key> <value>
 		if len(parts) < 2 {
 			return nil, fmt.Errorf("invalid NFS metric line %q", line)
@@ -40,7 +57,7 @@ func ParseClientRPCStats(r io.Reader) (*ClientRPCStats, error) {
 			return nil, fmt.Errorf("error parsing NFS metric line: %s", err)
 		}
 
-		switch metricLine := parts[0]; metricLine {
+		switch parts[0] {
 		case "net":
 			stats.Network, err = parseNetwork(values)
 		case "rpc":
@@ -51,8 +68,9 @@ func ParseClientRPCStats(r io.Reader) (*ClientRPCStats, error) {
 			stats.V3Stats, err = parseV3Stats(values)
 		case "proc4":
 			stats.ClientV4Stats, err = parseClientV4Stats(values)
-		default:
-			return nil, fmt.Errorf("unknown NFS metric line %q", metricLine)
+			if err == nil {
+				stats.V4OpCounts, err = parseV4OpCounts(values, clientV4Procedures)
+			}
 		}
 		if err != nil {
 			return nil, fmt.Errorf("errors parsing NFS metric line: %s", err)
@@ -30,7 +30,7 @@ func TestNewNFSClientRPCStats(t *testing.T) {
 	}{
 		{
 			name:    "invalid file",
-			content: "invalid",
+			content: "net",
 			invalid: true,
 		}, {
 			name: "good old kernel version file",
@@ -156,6 +156,19 @@ proc4 48 98 51 54 83 85 23 24 1 28 73 68 83 12 84 39 68 59 58 88 29 74 69 96 21
 					LayoutStats:        0,
 					Clone:              0,
 				},
+				V4OpCounts: map[string]uint64{
+					"NULL": 98, "READ": 51, "WRITE": 54, "COMMIT": 83, "OPEN": 85,
+					"OPEN_CONFIRM": 23, "OPEN_NOATTR": 24, "OPEN_DOWNGRADE": 1, "CLOSE": 28, "SETATTR": 73,
+					"FSINFO": 68, "RENEW": 83, "SETCLIENTID": 12, "SETCLIENTID_CONFIRM": 84, "LOCK": 39,
+					"LOCKT": 68, "LOCKU": 59, "ACCESS": 58, "GETATTR": 88, "LOOKUP": 29,
+					"LOOKUP_ROOT": 74, "REMOVE": 69, "RENAME": 96, "LINK": 21, "SYMLINK": 84,
+					"CREATE": 15, "PATHCONF": 53, "STATFS": 86, "READLINK": 54, "READDIR": 66,
+					"SERVER_CAPS": 56, "DELEGRETURN": 97, "GETACL": 36, "SETACL": 49, "FS_LOCATIONS": 32,
+					"RELEASE_LOCKOWNER": 85, "SECINFO": 81, "FSID_PRESENT": 11, "EXCHANGE_ID": 58, "CREATE_SESSION": 32,
+					"DESTROY_SESSION": 67, "SEQUENCE": 13, "GET_LEASE_TIME": 28, "RECLAIM_COMPLETE": 35, "LAYOUTGET": 90,
+					"GETDEVICEINFO": 1, "LAYOUTCOMMIT": 26, "LAYOUTRETURN": 1337,
+				},
+				Raw: map[string][]uint64{},
 			},
 		}, {
 			name: "good file",
@@ -282,6 +295,22 @@ proc4 61 1 0 0 0 0 0 0 0 0 0 0 0 1 1 0 0 0 0 0 0 0 2 0 0 0 0 0 0 0 0 0 0 0 0 0 0
 					LayoutStats:        0,
 					Clone:              0,
 				},
+				V4OpCounts: map[string]uint64{
+					"NULL": 1, "READ": 0, "WRITE": 0, "COMMIT": 0, "OPEN": 0,
+					"OPEN_CONFIRM": 0, "OPEN_NOATTR": 0, "OPEN_DOWNGRADE": 0, "CLOSE": 0, "SETATTR": 0,
+					"FSINFO": 0, "RENEW": 0, "SETCLIENTID": 1, "SETCLIENTID_CONFIRM": 1, "LOCK": 0,
+					"LOCKT": 0, "LOCKU": 0, "ACCESS": 0, "GETATTR": 0, "LOOKUP": 0,
+					"LOOKUP_ROOT": 0, "REMOVE": 2, "RENAME": 0, "LINK": 0, "SYMLINK": 0,
+					"CREATE": 0, "PATHCONF": 0, "STATFS": 0, "READLINK": 0, "READDIR": 0,
+					"SERVER_CAPS": 0, "DELEGRETURN": 0, "GETACL": 0, "SETACL": 0, "FS_LOCATIONS": 0,
+					"RELEASE_LOCKOWNER": 0, "SECINFO": 0, "FSID_PRESENT": 0, "EXCHANGE_ID": 0, "CREATE_SESSION": 0,
+					"DESTROY_SESSION": 0, "SEQUENCE": 0, "GET_LEASE_TIME": 0, "RECLAIM_COMPLETE": 0, "LAYOUTGET": 0,
+					"GETDEVICEINFO": 0, "LAYOUTCOMMIT": 0, "LAYOUTRETURN": 0, "SECINFO_NO_NAME": 0, "TEST_STATEID": 0,
+					"FREE_STATEID": 0, "GETDEVICELIST": 0, "BIND_CONN_TO_SESSION": 0, "DESTROY_CLIENTID": 0, "SEEK": 0,
+					"ALLOCATE": 0, "DEALLOCATE": 0, "LAYOUTSTATS": 0, "CLONE": 0, "COPY": 0,
+					"OFFLOAD_CANCEL": 0,
+				},
+				Raw: map[string][]uint64{},
 			},
 		},
 	}
@@ -303,3 +332,40 @@ proc4 61 1 0 0 0 0 0 0 0 0 0 0 0 1 1 0 0 0 0 0 0 0 2 0 0 0 0 0 0 0 0 0 0 0 0 0 0
 		})
 	}
 }
+
+func TestNFSClientRPCStatsUnknownLines(t *testing.T) {
+	content := `net 18628 0 18628 6
+rpc 4329785 0 4338291
+proc4 71 1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28 29 30 31 32 33 34 35 36 37 38 39 40 41 42 43 44 45 46 47 48 49 50 51 52 53 54 55 56 57 58 59 60 61 62 63 64 65 66 67 68 69 70 71
+proc5 3 1 2 3
+flag
+future some text
+future2 1 text
+`
+	stats, err := nfs.ParseClientRPCStats(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := map[string][]uint64{"proc5": {3, 1, 2, 3}, "flag": {}}, stats.Raw; !reflect.DeepEqual(want, have) {
+		t.Errorf("want raw lines %v, have %v", want, have)
+	}
+
+	for op, want := range map[string]uint64{
+		"NULL":        1,
+		"LAYOUTGET":   45,
+		"SEEK":        55,
+		"COPY":        60,
+		"READ_PLUS":   69,
+		"UNKNOWN_69":  70,
+		"UNKNOWN_70":  71,
+		"LOOKUP_ROOT": 21,
+	} {
+		if have := stats.V4OpCounts[op]; want != have {
+			t.Errorf("want %s count %d, have %d", op, want, have)
+		}
+	}
+	if want, have := uint64(59), stats.ClientV4Stats.Clone; want != have {
+		t.Errorf("want CLONE count %d, have %d", want, have)
+	}
+}
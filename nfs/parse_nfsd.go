@@ -22,14 +22,32 @@ import (
 	"github.com/prometheus/procfs/internal/util"
 )
 
-// ParseServerRPCStats returns stats read from /proc/net/rpc/nfsd
+// knownServerLines are the labels of the lines of /proc/net/rpc/nfsd which
+// are modeled by ServerRPCStats.
+var knownServerLines = map[string]bool{
+	"rc": true, "fh": true, "io": true, "th": true, "ra": true, "net": true,
+	"rpc": true, "proc2": true, "proc3": true, "proc4": true, "proc4ops": true,
+}
+
+// ParseServerRPCStats returns stats read from /proc/net/rpc/nfsd. Lines which
+// are not known to the parser are collected in the Raw map if all their
+// values are numeric, including lines without any value, and are skipped
+// otherwise.
 func ParseServerRPCStats(r io.Reader) (*ServerRPCStats, error) {
-	stats := &ServerRPCStats{}
+	stats := &ServerRPCStats{Raw: map[string][]uint64{}}
 
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
 		parts := strings.Fields(scanner.Text())
+		if len(parts) > 0 && !knownServerLines[parts[0]] {
+			// Lines added by newer kernels may have no or non-numeric values.
+			if values, err := util.ParseUint64s(parts[1:]); err == nil {
+				stats.Raw[parts[0]] = values
+			}
+			continue
+		}
+
 		// require at least <key> <value>
 		if len(parts) < 2 {
 			return nil, fmt.Errorf("invalid NFSd metric line %q", line)
@@ -50,7 +68,7 @@ func ParseServerRPCStats(r io.Reader) (*ServerRPCStats, error) {
 			return nil, fmt.Errorf("error parsing NFSd metric line: %s", err)
 		}
 
-		switch metricLine := parts[0]; metricLine {
+		switch label {
 		case "rc":
 			stats.ReplyCache, err = parseReplyCache(values)
 		case "fh":
@@ -73,8 +91,9 @@ func ParseServerRPCStats(r io.Reader) (*ServerRPCStats, error) {
 			stats.ServerV4Stats, err = parseServerV4Stats(values)
 		case "proc4ops":
 			stats.V4Ops, err = parseV4Ops(values)
-		default:
-			return nil, fmt.Errorf("unknown NFSd metric line %q", metricLine)
+			if err == nil {
+				stats.V4OpCounts, err = parseV4OpCounts(values, serverV4Operations)
+			}
 		}
 		if err != nil {
 			return nil, fmt.Errorf("errors parsing NFSd metric line: %s", err)
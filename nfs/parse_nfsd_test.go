@@ -30,7 +30,7 @@ func TestNewNFSdServerRPCStats(t *testing.T) {
 	}{
 		{
 			name:    "invalid file",
-			content: "invalid",
+			content: "net",
 			invalid: true,
 		}, {
 			name: "good file",
@@ -173,6 +173,23 @@ proc4ops 72 0 0 0 1098 2 0 0 0 0 8179 5896 0 0 0 0 5900 0 0 2 0 2 0 9609 0 2 150
 					Write:        3,
 					RelLockOwner: 0,
 				},
+				V4OpCounts: map[string]uint64{
+					"ACCESS": 1098, "CLOSE": 2, "COMMIT": 0, "CREATE": 0, "DELEGPURGE": 0,
+					"DELEGRETURN": 0, "GETATTR": 8179, "GETFH": 5896, "LINK": 0, "LOCK": 0,
+					"LOCKT": 0, "LOCKU": 0, "LOOKUP": 5900, "LOOKUPP": 0, "NVERIFY": 0,
+					"OPEN": 2, "OPENATTR": 0, "OPEN_CONFIRM": 2, "OPEN_DOWNGRADE": 0, "PUTFH": 9609,
+					"PUTPUBFH": 0, "PUTROOTFH": 2, "READ": 150, "READDIR": 1272, "READLINK": 0,
+					"REMOVE": 0, "RENAME": 0, "RENEW": 1236, "RESTOREFH": 0, "SAVEFH": 0,
+					"SECINFO": 0, "SETATTR": 0, "SETCLIENTID": 3, "SETCLIENTID_CONFIRM": 3, "VERIFY": 0,
+					"WRITE": 0, "RELEASE_LOCKOWNER": 0, "BACKCHANNEL_CTL": 0, "BIND_CONN_TO_SESSION": 0, "EXCHANGE_ID": 0,
+					"CREATE_SESSION": 0, "DESTROY_SESSION": 0, "FREE_STATEID": 0, "GET_DIR_DELEGATION": 0, "GETDEVICEINFO": 0,
+					"GETDEVICELIST": 0, "LAYOUTCOMMIT": 0, "LAYOUTGET": 0, "LAYOUTRETURN": 0, "SECINFO_NO_NAME": 0,
+					"SEQUENCE": 0, "SET_SSV": 0, "TEST_STATEID": 0, "WANT_DELEGATION": 0, "DESTROY_CLIENTID": 0,
+					"RECLAIM_COMPLETE": 0, "ALLOCATE": 0, "COPY": 0, "COPY_NOTIFY": 0, "DEALLOCATE": 0,
+					"IO_ADVISE": 0, "LAYOUTERROR": 0, "LAYOUTSTATS": 0, "OFFLOAD_CANCEL": 0, "OFFLOAD_STATUS": 0,
+					"READ_PLUS": 0, "SEEK": 0, "WRITE_SAME": 0, "CLONE": 0,
+				},
+				Raw: map[string][]uint64{},
 			},
 		},
 	}
@@ -194,3 +211,40 @@ proc4ops 72 0 0 0 1098 2 0 0 0 0 8179 5896 0 0 0 0 5900 0 0 2 0 2 0 9609 0 2 150
 		})
 	}
 }
+
+func TestNFSdServerRPCStatsUnknownLines(t *testing.T) {
+	content := `rpc 18628 0 0 0 0
+proc4ops 74 0 0 0 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28 29 30 31 32 33 34 35 36 37 38 39 40 41 42 43 44 45 46 47 48 49 50 51 52 53 54 55 56 57 58 59 60 61 62 63 64 65 66 67 68 69 70 71 72 73
+wdeleg_getattr 12
+flag
+future some text
+future2 1 text
+`
+	stats, err := nfs.ParseServerRPCStats(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := map[string][]uint64{"wdeleg_getattr": {12}, "flag": {}}, stats.Raw; !reflect.DeepEqual(want, have) {
+		t.Errorf("want raw lines %v, have %v", want, have)
+	}
+
+	for op, want := range map[string]uint64{
+		"ACCESS":    3,
+		"LOOKUPP":   16,
+		"LAYOUTGET": 50,
+		"ALLOCATE":  59,
+		"COPY":      60,
+		"SEEK":      69,
+		"CLONE":     71,
+		"GETXATTR":  72,
+		"SETXATTR":  73,
+	} {
+		if have := stats.V4OpCounts[op]; want != have {
+			t.Errorf("want %s count %d, have %d", op, want, have)
+		}
+	}
+	if _, ok := stats.V4OpCounts["LISTXATTRS"]; ok {
+		t.Errorf("want no LISTXATTRS count for a kernel without the operation")
+	}
+}
@@ -24,10 +24,13 @@ import (
 
 // Proc provides information about a running process.
 type Proc struct {
-	// The process ID.
+	// The process ID, or the thread ID for threads returned by AllThreads.
 	PID int
 
 	fs FS
+	// The ID of the process the thread belongs to, or 0 if this is a
+	// process.
+	tgid int
 }
 
 // Procs represents a list of Proc structs.
@@ -110,6 +113,37 @@ func (fs FS) AllProcs() (Procs, error) {
 	return p, nil
 }
 
+// AllThreads returns a list of all currently available threads of the
+// process, read from /proc/[pid]/task. The threads are returned as Proc, with
+// the thread ID as PID, and their methods read from /proc/[pid]/task/[tid]
+// instead of /proc/[pid].
+//
+// Threads may exit at any time, after which their methods return an error for
+// which os.IsNotExist is true.
+func (p Proc) AllThreads() (Procs, error) {
+	d, err := os.Open(p.path("task"))
+	if err != nil {
+		return Procs{}, err
+	}
+	defer d.Close()
+
+	names, err := d.Readdirnames(-1)
+	if err != nil {
+		return Procs{}, fmt.Errorf("could not read %s: %s", d.Name(), err)
+	}
+
+	t := Procs{}
+	for _, n := range names {
+		tid, err := strconv.ParseInt(n, 10, 64)
+		if err != nil {
+			continue
+		}
+		t = append(t, Proc{PID: int(tid), fs: p.fs, tgid: p.PID})
+	}
+
+	return t, nil
+}
+
 // CmdLine returns the command line of a process.
 func (p Proc) CmdLine() ([]string, error) {
 	f, err := os.Open(p.path("cmdline"))
@@ -156,6 +190,58 @@ func (p Proc) Executable() (string, error) {
 	return exe, err
 }
 
+// Cwd returns the absolute path to the current working directory of the
+// process.
+func (p Proc) Cwd() (string, error) {
+	wd, err := os.Readlink(p.path("cwd"))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+
+	return wd, err
+}
+
+// RootDir returns the absolute path to the process's root directory, as set
+// by chroot(2).
+func (p Proc) RootDir() (string, error) {
+	rdir, err := os.Readlink(p.path("root"))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+
+	return rdir, err
+}
+
+// Environ returns the environment of a process, as it was when the process
+// was started. Entries without a "=" map to the empty string.
+func (p Proc) Environ() (map[string]string, error) {
+	f, err := os.Open(p.path("environ"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	env := map[string]string{}
+	for _, e := range strings.Split(string(bytes.TrimRight(data, "\x00")), "\x00") {
+		if e == "" {
+			continue
+		}
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) == 2 {
+			env[kv[0]] = kv[1]
+		} else {
+			env[kv[0]] = ""
+		}
+	}
+
+	return env, nil
+}
+
 // FileDescriptors returns the currently open file descriptors of a process.
 func (p Proc) FileDescriptors() ([]uintptr, error) {
 	names, err := p.fileDescriptors()
@@ -234,5 +320,8 @@ func (p Proc) fileDescriptors() ([]string, error) {
 }
 
 func (p Proc) path(pa ...string) string {
+	if p.tgid != 0 {
+		return p.fs.Path(append([]string{strconv.Itoa(p.tgid), "task", strconv.Itoa(p.PID)}, pa...)...)
+	}
 	return p.fs.Path(append([]string{strconv.Itoa(p.PID)}, pa...)...)
 }
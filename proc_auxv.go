@@ -0,0 +1,191 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"unsafe"
+)
+
+// AuxvType is the type of an entry of the auxiliary vector, e.g. AT_PAGESZ.
+type AuxvType uint64
+
+// Types of auxiliary vector entries, see getauxval(3).
+const (
+	AuxvNull         AuxvType = 0
+	AuxvIgnore       AuxvType = 1
+	AuxvExecFD       AuxvType = 2
+	AuxvPHdr         AuxvType = 3
+	AuxvPHEnt        AuxvType = 4
+	AuxvPHNum        AuxvType = 5
+	AuxvPageSz       AuxvType = 6
+	AuxvBase         AuxvType = 7
+	AuxvFlags        AuxvType = 8
+	AuxvEntry        AuxvType = 9
+	AuxvNotELF       AuxvType = 10
+	AuxvUID          AuxvType = 11
+	AuxvEUID         AuxvType = 12
+	AuxvGID          AuxvType = 13
+	AuxvEGID         AuxvType = 14
+	AuxvPlatform     AuxvType = 15
+	AuxvHWCap        AuxvType = 16
+	AuxvClkTck       AuxvType = 17
+	AuxvSecure       AuxvType = 23
+	AuxvBasePlatform AuxvType = 24
+	AuxvRandom       AuxvType = 25
+	AuxvHWCap2       AuxvType = 26
+	AuxvExecFn       AuxvType = 31
+	AuxvSysinfo      AuxvType = 32
+	AuxvSysinfoEHdr  AuxvType = 33
+	AuxvMinSigStkSz  AuxvType = 51
+)
+
+var auxvTypeNames = map[AuxvType]string{
+	AuxvNull:         "AT_NULL",
+	AuxvIgnore:       "AT_IGNORE",
+	AuxvExecFD:       "AT_EXECFD",
+	AuxvPHdr:         "AT_PHDR",
+	AuxvPHEnt:        "AT_PHENT",
+	AuxvPHNum:        "AT_PHNUM",
+	AuxvPageSz:       "AT_PAGESZ",
+	AuxvBase:         "AT_BASE",
+	AuxvFlags:        "AT_FLAGS",
+	AuxvEntry:        "AT_ENTRY",
+	AuxvNotELF:       "AT_NOTELF",
+	AuxvUID:          "AT_UID",
+	AuxvEUID:         "AT_EUID",
+	AuxvGID:          "AT_GID",
+	AuxvEGID:         "AT_EGID",
+	AuxvPlatform:     "AT_PLATFORM",
+	AuxvHWCap:        "AT_HWCAP",
+	AuxvClkTck:       "AT_CLKTCK",
+	AuxvSecure:       "AT_SECURE",
+	AuxvBasePlatform: "AT_BASE_PLATFORM",
+	AuxvRandom:       "AT_RANDOM",
+	AuxvHWCap2:       "AT_HWCAP2",
+	AuxvExecFn:       "AT_EXECFN",
+	AuxvSysinfo:      "AT_SYSINFO",
+	AuxvSysinfoEHdr:  "AT_SYSINFO_EHDR",
+	AuxvMinSigStkSz:  "AT_MINSIGSTKSZ",
+}
+
+func (t AuxvType) String() string {
+	if name, ok := auxvTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("AT_%d", uint64(t))
+}
+
+// Auxv returns the auxiliary vector of the process, which holds information
+// passed by the kernel on execve(2), keyed by entry type. Values which are
+// addresses, like AT_RANDOM, point into the memory of the process.
+//
+// The vector is decoded using the word size and byte order of the running
+// binary. If it does not fit, e.g. for 32-bit processes on a 64-bit kernel
+// or snapshots taken on another architecture, the other layouts are tried.
+func (p Proc) Auxv() (map[AuxvType]uint64, error) {
+	f, err := os.Open(p.path("auxv"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	auxv, err := parseAuxv(data)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %s", f.Name(), err)
+	}
+
+	return auxv, nil
+}
+
+// auxvLayout is a word size and byte order the auxiliary vector may be
+// encoded with.
+type auxvLayout struct {
+	size  int
+	order binary.ByteOrder
+}
+
+func parseAuxv(data []byte) (map[AuxvType]uint64, error) {
+	// Kernel threads have no auxiliary vector.
+	if len(data) == 0 {
+		return map[AuxvType]uint64{}, nil
+	}
+
+	native := auxvLayout{size: strconv.IntSize / 8, order: nativeEndian()}
+	layouts := []auxvLayout{native}
+	for _, size := range []int{8, 4} {
+		for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+			if l := (auxvLayout{size: size, order: order}); l != native {
+				layouts = append(layouts, l)
+			}
+		}
+	}
+
+	for _, l := range layouts {
+		if auxv, ok := l.decode(data); ok {
+			return auxv, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown auxiliary vector layout")
+}
+
+// decode decodes the auxiliary vector if it is valid for the layout: a
+// sequence of type and value pairs with types in the range used by the
+// kernel, terminated by an AT_NULL entry.
+func (l auxvLayout) decode(data []byte) (map[AuxvType]uint64, bool) {
+	word := func(b []byte) uint64 {
+		if l.size == 8 {
+			return l.order.Uint64(b)
+		}
+		return uint64(l.order.Uint32(b))
+	}
+
+	if len(data)%(2*l.size) != 0 {
+		return nil, false
+	}
+
+	auxv := map[AuxvType]uint64{}
+	for i := 0; i < len(data); i += 2 * l.size {
+		t, v := AuxvType(word(data[i:])), word(data[i+l.size:])
+		if t == AuxvNull {
+			return auxv, i+2*l.size == len(data)
+		}
+		if t > 0xffff {
+			return nil, false
+		}
+		auxv[t] = v
+	}
+
+	// The vector was not terminated.
+	return nil, false
+}
+
+// nativeEndian returns the byte order of the running binary.
+func nativeEndian() binary.ByteOrder {
+	var i uint16 = 1
+	if *(*byte)(unsafe.Pointer(&i)) == 1 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}
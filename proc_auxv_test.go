@@ -0,0 +1,105 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestAuxv(t *testing.T) {
+	for _, tt := range []struct {
+		process int
+		want    map[AuxvType]uint64
+	}{
+		{
+			process: 26231,
+			want: map[AuxvType]uint64{
+				AuxvSysinfoEHdr: 0x7ffd6a5e2000,
+				AuxvHWCap:       0xbfebfbff,
+				AuxvPageSz:      4096,
+				AuxvClkTck:      100,
+				AuxvPHdr:        0x55680ae1e040,
+				AuxvPHEnt:       56,
+				AuxvPHNum:       9,
+				AuxvBase:        0x7f3b5c1d2000,
+				AuxvEntry:       0x55680ae21b20,
+				AuxvUID:         1000,
+				AuxvEUID:        1000,
+				AuxvGID:         1000,
+				AuxvEGID:        1000,
+				AuxvSecure:      0,
+				AuxvRandom:      0x7ffd6a4c1b19,
+				AuxvHWCap2:      2,
+				AuxvExecFn:      0x7ffd6a4c2fe8,
+				AuxvPlatform:    0x7ffd6a4c1b29,
+			},
+		},
+		{process: 26232, want: map[AuxvType]uint64{}},
+	} {
+		p, err := FS("fixtures").NewProc(tt.process)
+		if err != nil {
+			t.Fatal(err)
+		}
+		auxv, err := p.Auxv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tt.want, auxv) {
+			t.Errorf("want auxv %v, have %v", tt.want, auxv)
+		}
+	}
+}
+
+func TestParseAuxvLayouts(t *testing.T) {
+	want := map[AuxvType]uint64{AuxvPageSz: 16384, AuxvClkTck: 100}
+
+	for _, l := range []auxvLayout{
+		{size: 8, order: binary.LittleEndian},
+		{size: 8, order: binary.BigEndian},
+		{size: 4, order: binary.LittleEndian},
+		{size: 4, order: binary.BigEndian},
+	} {
+		data := make([]byte, 6*l.size)
+		for i, w := range []uint64{uint64(AuxvPageSz), 16384, uint64(AuxvClkTck), 100, 0, 0} {
+			if l.size == 8 {
+				l.order.PutUint64(data[i*l.size:], w)
+			} else {
+				l.order.PutUint32(data[i*l.size:], uint32(w))
+			}
+		}
+
+		have, err := parseAuxv(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, have) {
+			t.Errorf("%d bytes %s: want auxv %v, have %v", l.size, l.order, want, have)
+		}
+	}
+
+	if _, err := parseAuxv([]byte{6, 0, 0, 0, 0, 16, 0}); err == nil {
+		t.Error("want error for truncated auxv, have none")
+	}
+}
+
+func TestAuxvTypeString(t *testing.T) {
+	if want, have := "AT_CLKTCK", AuxvClkTck.String(); want != have {
+		t.Errorf("want %s, have %s", want, have)
+	}
+	if want, have := "AT_99", AuxvType(99).String(); want != have {
+		t.Errorf("want %s, have %s", want, have)
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Cgroup models one line from /proc/[pid]/cgroup. Each line describes the
+// membership of the process in one cgroup hierarchy. On systems using
+// cgroup v1 there is one line per hierarchy, on systems using cgroup v2
+// there is a single line for the unified hierarchy, and hybrid systems have
+// both.
+type Cgroup struct {
+	// HierarchyID is the ID of the cgroup v1 hierarchy, or 0 for the cgroup
+	// v2 unified hierarchy.
+	HierarchyID int
+	// Controllers are the controllers bound to the hierarchy, e.g. "cpu" or
+	// "name=systemd" for named hierarchies. It is empty for cgroup v2.
+	Controllers []string
+	// Path is the path of the cgroup, relative to the root of the
+	// hierarchy.
+	Path string
+	// Dir is the directory of the cgroup, resolved using the cgroup mounts
+	// listed in /proc/self/mountinfo. It is empty if the hierarchy is not
+	// mounted or the cgroup is outside of the mounted subtree.
+	Dir string
+}
+
+// Unified returns whether the cgroup belongs to the cgroup v2 unified
+// hierarchy.
+func (c Cgroup) Unified() bool {
+	return c.HierarchyID == 0 && len(c.Controllers) == 0
+}
+
+// Cgroups reads from /proc/[pid]/cgroup and returns the cgroups the process
+// is a member of.
+//
+// The cgroup directories are resolved using the mounts of the reading
+// process, read from /proc/self/mountinfo of the same FS, so that the
+// directories can be accessed by the caller.
+func (p Proc) Cgroups() ([]Cgroup, error) {
+	f, err := os.Open(p.path("cgroup"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cgroups, err := parseCgroups(f)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %s", f.Name(), err)
+	}
+
+	mounts, err := p.fs.cgroupMounts()
+	if err != nil {
+		return nil, err
+	}
+	for i := range cgroups {
+		cgroups[i].Dir = mounts.resolve(cgroups[i])
+	}
+
+	return cgroups, nil
+}
+
+func parseCgroups(r io.Reader) ([]Cgroup, error) {
+	cgroups := []Cgroup{}
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		if s.Text() == "" {
+			continue
+		}
+
+		// The path may contain colons, so split into at most three parts.
+		parts := strings.SplitN(s.Text(), ":", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("unexpected cgroup line %q", s.Text())
+		}
+
+		id, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid hierarchy ID %q: %s", parts[0], err)
+		}
+
+		c := Cgroup{HierarchyID: id, Controllers: []string{}, Path: parts[2]}
+		if parts[1] != "" {
+			c.Controllers = strings.Split(parts[1], ",")
+		}
+		cgroups = append(cgroups, c)
+	}
+
+	return cgroups, s.Err()
+}
+
+// cgroupMounts are the mounted cgroup hierarchies.
+type cgroupMounts []*MountInfo
+
+// cgroupMounts returns the cgroup mounts of the reading process. If
+// /proc/self/mountinfo does not exist, no mounts are returned.
+func (fs FS) cgroupMounts() (cgroupMounts, error) {
+	f, err := os.Open(fs.Path("self", "mountinfo"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	mounts, err := parseMountInfo(f)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %s", f.Name(), err)
+	}
+
+	cgroups := cgroupMounts{}
+	for _, m := range mounts {
+		if m.FSType == "cgroup" || m.FSType == "cgroup2" {
+			cgroups = append(cgroups, m)
+		}
+	}
+
+	return cgroups, nil
+}
+
+// resolve returns the directory of the cgroup, or the empty string if it is
+// not visible in any of the mounts.
+func (ms cgroupMounts) resolve(c Cgroup) string {
+	for _, m := range ms {
+		if (m.FSType == "cgroup2") != c.Unified() || !hasControllers(m, c.Controllers) {
+			continue
+		}
+
+		if m.Root == "/" {
+			return path.Join(m.MountPoint, c.Path)
+		}
+		if c.Path == m.Root {
+			return m.MountPoint
+		}
+		if strings.HasPrefix(c.Path, m.Root+"/") {
+			return path.Join(m.MountPoint, strings.TrimPrefix(c.Path, m.Root))
+		}
+	}
+
+	return ""
+}
+
+// hasControllers returns whether all controllers are bound to the cgroup v1
+// hierarchy of the mount, which are listed in its super options. Named
+// hierarchies are listed as "name=<name>".
+func hasControllers(m *MountInfo, controllers []string) bool {
+	for _, c := range controllers {
+		kv := strings.SplitN(c, "=", 2)
+		v, ok := m.SuperOptions[kv[0]]
+		if !ok || (len(kv) == 2 && v != kv[1]) {
+			return false
+		}
+	}
+	return true
+}
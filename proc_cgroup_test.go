@@ -0,0 +1,105 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProcCgroups(t *testing.T) {
+	for _, tt := range []struct {
+		pid  int
+		want []Cgroup
+	}{
+		{
+			// Hybrid layout.
+			pid: 26231,
+			want: []Cgroup{
+				{
+					HierarchyID: 12,
+					Controllers: []string{"pids"},
+					Path:        "/user.slice/user-1000.slice",
+				},
+				{
+					HierarchyID: 5,
+					Controllers: []string{"blkio"},
+					Path:        "/docker/c0ffee/inner",
+					Dir:         "/sys/fs/cgroup/blkio/inner",
+				},
+				{
+					HierarchyID: 4,
+					Controllers: []string{"memory"},
+					Path:        "/user.slice",
+					Dir:         "/sys/fs/cgroup/memory/user.slice",
+				},
+				{
+					HierarchyID: 3,
+					Controllers: []string{"cpu", "cpuacct"},
+					Path:        "/user.slice",
+					Dir:         "/sys/fs/cgroup/cpu,cpuacct/user.slice",
+				},
+				{
+					HierarchyID: 1,
+					Controllers: []string{"name=systemd"},
+					Path:        "/user.slice/user-1000.slice/session-1.scope",
+					Dir:         "/sys/fs/cgroup/systemd/user.slice/user-1000.slice/session-1.scope",
+				},
+				{
+					HierarchyID: 0,
+					Controllers: []string{},
+					Path:        "/user.slice/user-1000.slice/session-1.scope",
+					Dir:         "/sys/fs/cgroup/unified/user.slice/user-1000.slice/session-1.scope",
+				},
+			},
+		},
+		{
+			// Unified layout.
+			pid: 26232,
+			want: []Cgroup{
+				{
+					HierarchyID: 0,
+					Controllers: []string{},
+					Path:        "/system.slice/docker-abc.scope",
+					Dir:         "/sys/fs/cgroup/unified/system.slice/docker-abc.scope",
+				},
+			},
+		},
+	} {
+		p, err := FS("fixtures").NewProc(tt.pid)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		have, err := p.Cgroups()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tt.want, have) {
+			t.Errorf("%d: want cgroups %+v, have %+v", tt.pid, tt.want, have)
+		}
+	}
+}
+
+func TestParseCgroupsInvalid(t *testing.T) {
+	for _, in := range []string{
+		"0:/\n",
+		"x::/\n",
+	} {
+		if _, err := parseCgroups(strings.NewReader(in)); err == nil {
+			t.Errorf("want error parsing %q, have none", in)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProcFDInfo contains the details of an open file descriptor, read from
+// /proc/[pid]/fdinfo/[fd].
+type ProcFDInfo struct {
+	// The file descriptor number.
+	FD uintptr
+	// The current offset of the file.
+	Pos int64
+	// The file access mode and status flags, including O_CLOEXEC if set on
+	// the file descriptor.
+	Flags FileFlags
+	// The ID of the mount containing the file, see MountInfo. Available
+	// since Linux 3.15.
+	MntID int
+
+	// The counter of an eventfd, or nil for other files.
+	EventFDCount *uint64
+	// The files monitored by an epoll instance.
+	EPollTargets []EPollTarget
+	// The watches of an inotify instance.
+	InotifyWatches []InotifyWatch
+	// The settings of a timerfd, or nil for other files.
+	TimerFD *TimerFDInfo
+}
+
+// EPollTarget is a file descriptor monitored by an epoll instance.
+type EPollTarget struct {
+	// The monitored file descriptor number.
+	FD int
+	// The event mask, e.g. EPOLLIN.
+	Events uint32
+	// The user data registered along with the file descriptor.
+	Data uint64
+	// The offset of the monitored file. Available since Linux 3.15.
+	Pos int64
+	// The inode number of the monitored file and the device it resides on.
+	// Available since Linux 3.15.
+	Ino  uint64
+	SDev uint32
+}
+
+// InotifyWatch is a watch of an inotify instance.
+type InotifyWatch struct {
+	// The watch descriptor.
+	WD int
+	// The inode number of the watched file and the device it resides on.
+	Ino  uint64
+	SDev uint32
+	// The mask of events to watch for, e.g. IN_MODIFY.
+	Mask uint32
+	// The mask of events to ignore.
+	IgnoredMask uint32
+}
+
+// TimerFDInfo holds the settings of a timerfd.
+type TimerFDInfo struct {
+	// The clock the timer is measured against, e.g. 0 for
+	// CLOCK_REALTIME.
+	ClockID int
+	// The number of timer expirations that have occurred.
+	Ticks uint64
+	// The flags of the last timerfd_settime(2) call, e.g.
+	// TFD_TIMER_ABSTIME.
+	SettimeFlags int
+	// The time until the next expiration, and the interval of the timer.
+	Value    time.Duration
+	Interval time.Duration
+}
+
+// FDInfo returns the details of the given file descriptor of the process.
+func (p Proc) FDInfo(fd uintptr) (ProcFDInfo, error) {
+	f, err := os.Open(p.path("fdinfo", strconv.FormatUint(uint64(fd), 10)))
+	if err != nil {
+		return ProcFDInfo{}, err
+	}
+	defer f.Close()
+
+	info, err := parseFDInfo(f)
+	if err != nil {
+		return ProcFDInfo{}, fmt.Errorf("couldn't parse %s: %s", f.Name(), err)
+	}
+	info.FD = fd
+
+	return info, nil
+}
+
+// FDInfos returns the details of all open file descriptors of the process,
+// ordered by file descriptor number. File descriptors closed while they are
+// read are skipped.
+func (p Proc) FDInfos() ([]ProcFDInfo, error) {
+	fds, err := p.FileDescriptors()
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(fdsByNumber(fds))
+
+	infos := make([]ProcFDInfo, 0, len(fds))
+	for _, fd := range fds {
+		info, err := p.FDInfo(fd)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// fdsByNumber sorts file descriptors by number.
+type fdsByNumber []uintptr
+
+func (fds fdsByNumber) Len() int           { return len(fds) }
+func (fds fdsByNumber) Swap(i, j int)      { fds[i], fds[j] = fds[j], fds[i] }
+func (fds fdsByNumber) Less(i, j int) bool { return fds[i] < fds[j] }
+
+func parseFDInfo(r io.Reader) (ProcFDInfo, error) {
+	var (
+		info ProcFDInfo
+		err  error
+	)
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		switch {
+		case strings.HasPrefix(line, "tfd:"):
+			t, err := parseEPollTarget(line)
+			if err != nil {
+				return ProcFDInfo{}, err
+			}
+			info.EPollTargets = append(info.EPollTargets, t)
+			continue
+		case strings.HasPrefix(line, "inotify "):
+			w, err := parseInotifyWatch(line)
+			if err != nil {
+				return ProcFDInfo{}, err
+			}
+			info.InotifyWatches = append(info.InotifyWatches, w)
+			continue
+		}
+
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key, value := kv[0], strings.TrimSpace(kv[1])
+
+		switch key {
+		case "pos":
+			info.Pos, err = strconv.ParseInt(value, 10, 64)
+		case "flags":
+			var v uint64
+			v, err = strconv.ParseUint(value, 8, 32)
+			info.Flags = FileFlags(v)
+		case "mnt_id":
+			info.MntID, err = strconv.Atoi(value)
+		case "eventfd-count":
+			var v uint64
+			v, err = strconv.ParseUint(value, 16, 64)
+			info.EventFDCount = &v
+		case "clockid", "ticks", "settime flags", "it_value", "it_interval":
+			if info.TimerFD == nil {
+				info.TimerFD = &TimerFDInfo{}
+			}
+			err = info.TimerFD.parse(key, value)
+		}
+		if err != nil {
+			return ProcFDInfo{}, fmt.Errorf("invalid value for %s: %s", key, err)
+		}
+	}
+
+	return info, s.Err()
+}
+
+func (t *TimerFDInfo) parse(key, value string) error {
+	var err error
+	switch key {
+	case "clockid":
+		t.ClockID, err = strconv.Atoi(value)
+	case "ticks":
+		t.Ticks, err = strconv.ParseUint(value, 10, 64)
+	case "settime flags":
+		var v int64
+		v, err = strconv.ParseInt(value, 8, 32)
+		t.SettimeFlags = int(v)
+	case "it_value":
+		t.Value, err = parseTimespec(value)
+	case "it_interval":
+		t.Interval, err = parseTimespec(value)
+	}
+	return err
+}
+
+// parseTimespec parses a time in the "(seconds, nanoseconds)" format.
+func parseTimespec(s string) (time.Duration, error) {
+	var sec, nsec int64
+	if _, err := fmt.Sscanf(s, "(%d, %d)", &sec, &nsec); err != nil {
+		return 0, fmt.Errorf("invalid time %q: %s", s, err)
+	}
+	return time.Duration(sec)*time.Second + time.Duration(nsec), nil
+}
+
+// parseEPollTarget parses an epoll target line:
+//
+//	tfd:        5 events:       1d data: ffffffffffffffff  pos:0 ino:61af sdev:7
+func parseEPollTarget(line string) (EPollTarget, error) {
+	var t EPollTarget
+
+	for key, value := range parseFDInfoFields(line) {
+		var err error
+		switch key {
+		case "tfd":
+			t.FD, err = strconv.Atoi(value)
+		case "events":
+			var v uint64
+			v, err = strconv.ParseUint(value, 16, 32)
+			t.Events = uint32(v)
+		case "data":
+			t.Data, err = strconv.ParseUint(value, 16, 64)
+		case "pos":
+			t.Pos, err = strconv.ParseInt(value, 10, 64)
+		case "ino":
+			t.Ino, err = strconv.ParseUint(value, 16, 64)
+		case "sdev":
+			var v uint64
+			v, err = strconv.ParseUint(value, 16, 32)
+			t.SDev = uint32(v)
+		}
+		if err != nil {
+			return EPollTarget{}, fmt.Errorf("invalid epoll target %s %q: %s", key, value, err)
+		}
+	}
+
+	return t, nil
+}
+
+// parseInotifyWatch parses an inotify watch line:
+//
+//	inotify wd:3 ino:9e7e sdev:800013 mask:800afce ignored_mask:0 fhandle-bytes:8 fhandle-type:1 f_handle:7e9e0000640d1b6d
+func parseInotifyWatch(line string) (InotifyWatch, error) {
+	var w InotifyWatch
+
+	for key, value := range parseFDInfoFields(strings.TrimPrefix(line, "inotify ")) {
+		var (
+			v   uint64
+			err error
+		)
+		switch key {
+		case "wd":
+			w.WD, err = strconv.Atoi(value)
+		case "ino":
+			w.Ino, err = strconv.ParseUint(value, 16, 64)
+		case "sdev":
+			v, err = strconv.ParseUint(value, 16, 32)
+			w.SDev = uint32(v)
+		case "mask":
+			v, err = strconv.ParseUint(value, 16, 32)
+			w.Mask = uint32(v)
+		case "ignored_mask":
+			v, err = strconv.ParseUint(value, 16, 32)
+			w.IgnoredMask = uint32(v)
+		}
+		if err != nil {
+			return InotifyWatch{}, fmt.Errorf("invalid inotify watch %s %q: %s", key, value, err)
+		}
+	}
+
+	return w, nil
+}
+
+// parseFDInfoFields splits a line of "key:value" pairs into a map. The
+// value may be separated from the key by whitespace.
+func parseFDInfoFields(line string) map[string]string {
+	kvs := map[string]string{}
+
+	fields := strings.Fields(line)
+	for i := 0; i < len(fields); i++ {
+		kv := strings.SplitN(fields[i], ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		if kv[1] == "" && i+1 < len(fields) {
+			i++
+			kv[1] = fields[i]
+		}
+		kvs[kv[0]] = kv[1]
+	}
+
+	return kvs
+}
+
+// FileFlags are the file access mode and status flags of an open file, as
+// passed to open(2). The flag values depend on the architecture; they are
+// decoded using the values of the architecture the package is built for.
+type FileFlags uint32
+
+const accessModeMask FileFlags = 03
+
+var accessModeNames = []string{"O_RDONLY", "O_WRONLY", "O_RDWR"}
+
+// fileFlagNames are the names of the flags. O_SYNC and O_TMPFILE are
+// composite flags, which also set O_DSYNC and O_DIRECTORY respectively, so
+// they precede their parts.
+var fileFlagNames = []struct {
+	flag FileFlags
+	name string
+}{
+	{oCreat, "O_CREAT"},
+	{oExcl, "O_EXCL"},
+	{oNoctty, "O_NOCTTY"},
+	{oTrunc, "O_TRUNC"},
+	{oAppend, "O_APPEND"},
+	{oNonblock, "O_NONBLOCK"},
+	{oSync | oDSync, "O_SYNC"},
+	{oDSync, "O_DSYNC"},
+	{oAsync, "O_ASYNC"},
+	{oDirect, "O_DIRECT"},
+	{oLargefile, "O_LARGEFILE"},
+	{oTmpfile | oDirectory, "O_TMPFILE"},
+	{oDirectory, "O_DIRECTORY"},
+	{oNofollow, "O_NOFOLLOW"},
+	{oNoatime, "O_NOATIME"},
+	{oCloexec, "O_CLOEXEC"},
+	{oPath, "O_PATH"},
+}
+
+// CloseOnExec returns whether the file descriptor is closed on execve(2).
+func (ff FileFlags) CloseOnExec() bool {
+	return ff&oCloexec != 0
+}
+
+// Names returns the access mode, e.g. "O_RDWR", followed by the names of
+// the set flags, e.g. "O_NONBLOCK". Flags unknown to this package are
+// returned as a single octal number.
+func (ff FileFlags) Names() []string {
+	names := []string{}
+	if mode := int(ff & accessModeMask); mode < len(accessModeNames) {
+		names = append(names, accessModeNames[mode])
+	} else {
+		names = append(names, fmt.Sprintf("0%o", mode))
+	}
+
+	rest := ff &^ accessModeMask
+	for _, f := range fileFlagNames {
+		if rest&f.flag == f.flag {
+			names = append(names, f.name)
+			rest &^= f.flag
+		}
+	}
+	if rest != 0 {
+		names = append(names, fmt.Sprintf("0%o", uint32(rest)))
+	}
+
+	return names
+}
@@ -0,0 +1,39 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build mips || mipsle || mips64 || mips64le
+// +build mips mipsle mips64 mips64le
+
+package procfs
+
+// Open flags as defined by arch/mips/include/uapi/asm/fcntl.h. oSync and
+// oTmpfile are the kernel-internal __O_SYNC and __O_TMPFILE bits.
+const (
+	oCreat     = 0x0100
+	oExcl      = 0x0400
+	oNoctty    = 0x0800
+	oTrunc     = 0x0200
+	oAppend    = 0x0008
+	oNonblock  = 0x0080
+	oDSync     = 0x0010
+	oAsync     = 0x1000
+	oDirect    = 0x8000
+	oLargefile = 0x2000
+	oDirectory = 0200000
+	oNofollow  = 0400000
+	oNoatime   = 01000000
+	oCloexec   = 02000000
+	oSync      = 0x4000
+	oPath      = 010000000
+	oTmpfile   = 020000000
+)
@@ -0,0 +1,39 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ppc64 || ppc64le
+// +build ppc64 ppc64le
+
+package procfs
+
+// Open flags as defined by arch/powerpc/include/uapi/asm/fcntl.h. oSync and
+// oTmpfile are the kernel-internal __O_SYNC and __O_TMPFILE bits.
+const (
+	oCreat     = 0100
+	oExcl      = 0200
+	oNoctty    = 0400
+	oTrunc     = 01000
+	oAppend    = 02000
+	oNonblock  = 04000
+	oDSync     = 010000
+	oAsync     = 020000
+	oDirect    = 0400000
+	oLargefile = 0200000
+	oDirectory = 040000
+	oNofollow  = 0100000
+	oNoatime   = 01000000
+	oCloexec   = 02000000
+	oSync      = 04000000
+	oPath      = 010000000
+	oTmpfile   = 020000000
+)
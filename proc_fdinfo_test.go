@@ -0,0 +1,136 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFDInfo(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := p.FDInfo(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ProcFDInfo{FD: 0, Flags: 02004002, MntID: 13}
+	if !reflect.DeepEqual(want, info) {
+		t.Errorf("want %+v, have %+v", want, info)
+	}
+	if want, have := []string{"O_RDWR", "O_NONBLOCK", "O_CLOEXEC"}, info.Flags.Names(); !reflect.DeepEqual(want, have) {
+		t.Errorf("want flags %v, have %v", want, have)
+	}
+	if !info.Flags.CloseOnExec() {
+		t.Error("want close on exec, have none")
+	}
+
+	if _, err := p.FDInfo(4); err == nil {
+		t.Error("want error for non-existing fd, have none")
+	}
+}
+
+func TestFDInfos(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	infos, err := p.FDInfos()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 5, len(infos); want != have {
+		t.Fatalf("want %d fds, have %d", want, have)
+	}
+
+	count := uint64(0x2a)
+	for i, want := range []ProcFDInfo{
+		{FD: 0, Flags: 02004002, MntID: 13},
+		{FD: 1, Flags: 02004002, MntID: 14, EventFDCount: &count},
+		{
+			FD:    2,
+			Flags: 02,
+			MntID: 14,
+			EPollTargets: []EPollTarget{
+				{FD: 10, Events: 0x19, Data: 0x7f00000000000a, Ino: 0x61af, SDev: 0x7},
+				{FD: 1, Events: 0x1d, Data: 0xffffffffffffffff, Ino: 0x1057, SDev: 0xe},
+			},
+		},
+		{
+			FD:    3,
+			MntID: 14,
+			InotifyWatches: []InotifyWatch{
+				{WD: 3, Ino: 0x9e7e, SDev: 0x800013, Mask: 0x800afce},
+				{WD: 2, Ino: 0xa111, SDev: 0x800013, Mask: 0x800afce},
+			},
+		},
+		{
+			FD:    10,
+			Flags: 02004000,
+			MntID: 14,
+			TimerFD: &TimerFDInfo{
+				ClockID:      1,
+				Ticks:        3,
+				SettimeFlags: 1,
+				Value:        49406829 * time.Nanosecond,
+				Interval:     time.Second,
+			},
+		},
+	} {
+		if have := infos[i]; !reflect.DeepEqual(want, have) {
+			t.Errorf("fd %d: want %+v, have %+v", want.FD, want, have)
+		}
+	}
+}
+
+func TestParseFDInfoInvalid(t *testing.T) {
+	for _, in := range []string{
+		"pos:\tx\n",
+		"flags:\t9\n",
+		"eventfd-count: xyz\n",
+		"it_value: 0, 1\n",
+		"tfd:        x events:       1d data: ffffffffffffffff  pos:0 ino:61af sdev:7\n",
+		"inotify wd:3 ino:zz sdev:800013 mask:800afce ignored_mask:0\n",
+	} {
+		if _, err := parseFDInfo(strings.NewReader(in)); err == nil {
+			t.Errorf("want error parsing %q, have none", in)
+		}
+	}
+}
+
+func TestFileFlagsNames(t *testing.T) {
+	for _, tt := range []struct {
+		flags FileFlags
+		want  []string
+	}{
+		{flags: 0, want: []string{"O_RDONLY"}},
+		{flags: 01 | oCreat, want: []string{"O_WRONLY", "O_CREAT"}},
+		{flags: 02 | oLargefile | 040000000, want: []string{"O_RDWR", "O_LARGEFILE", "040000000"}},
+		{flags: 03, want: []string{"03"}},
+		{flags: 01 | oSync | oDSync, want: []string{"O_WRONLY", "O_SYNC"}},
+		{flags: 01 | oDSync, want: []string{"O_WRONLY", "O_DSYNC"}},
+		{flags: 02 | oTmpfile | oDirectory, want: []string{"O_RDWR", "O_TMPFILE"}},
+		{flags: oDirectory | oNofollow, want: []string{"O_RDONLY", "O_DIRECTORY", "O_NOFOLLOW"}},
+	} {
+		if have := tt.flags.Names(); !reflect.DeepEqual(tt.want, have) {
+			t.Errorf("%o: want %v, have %v", uint32(tt.flags), tt.want, have)
+		}
+	}
+}
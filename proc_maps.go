@@ -0,0 +1,341 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ProcMapPermissions contains the permissions of a memory mapping.
+type ProcMapPermissions struct {
+	Read    bool
+	Write   bool
+	Execute bool
+	// Whether the mapping is shared with other processes. Otherwise it is
+	// private, i.e. copy on write.
+	Shared bool
+}
+
+// ProcMap is a memory mapping of a process, read from /proc/[pid]/maps.
+type ProcMap struct {
+	// The start and end address of the mapping.
+	StartAddr uintptr
+	EndAddr   uintptr
+	// The permissions of the mapping.
+	Perms ProcMapPermissions
+	// The offset into the mapped file.
+	Offset int64
+	// The major and minor number of the device holding the mapped file.
+	DevMajor uint32
+	DevMinor uint32
+	// The inode of the mapped file, or 0 for anonymous mappings.
+	Inode uint64
+	// The path of the mapped file, a pseudo-path like "[heap]" or "[stack]",
+	// or empty for anonymous mappings.
+	Pathname string
+}
+
+// ProcMaps returns the memory mappings of the process.
+func (p Proc) ProcMaps() ([]ProcMap, error) {
+	f, err := os.Open(p.path("maps"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	maps := []ProcMap{}
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		m, err := parseProcMap(s.Text())
+		if err != nil {
+			return nil, err
+		}
+		maps = append(maps, m)
+	}
+
+	return maps, s.Err()
+}
+
+// SMapsMemory holds the memory usage of one or more mappings, as reported
+// in /proc/[pid]/smaps and /proc/[pid]/smaps_rollup. All values are in
+// bytes.
+type SMapsMemory struct {
+	// Amount of the mapping currently resident in memory.
+	Rss uint64
+	// Proportional set size: resident memory, with each page shared with
+	// other processes divided by the number of processes sharing it.
+	Pss uint64
+	// Resident pages shared with other processes, which are clean or dirty.
+	SharedClean uint64
+	SharedDirty uint64
+	// Resident pages private to this process, which are clean or dirty.
+	PrivateClean uint64
+	PrivateDirty uint64
+	// Amount of memory currently marked as referenced or accessed.
+	Referenced uint64
+	// Amount of memory that does not belong to any file.
+	Anonymous uint64
+	// Amount of memory backed by transparent huge pages.
+	AnonHugePages uint64
+	// Amount of anonymous memory that is swapped out.
+	Swap uint64
+	// Proportional swap size, like Pss for swapped out pages.
+	SwapPss uint64
+	// Amount of memory locked into memory.
+	Locked uint64
+}
+
+// ProcSMap is a memory mapping of a process along with its memory usage,
+// read from /proc/[pid]/smaps.
+type ProcSMap struct {
+	ProcMap
+	SMapsMemory
+
+	// Size of the mapping.
+	Size uint64
+	// Page size used by the kernel and the MMU to back the mapping.
+	KernelPageSize uint64
+	MMUPageSize    uint64
+	// Flags associated with the mapping, e.g. "rd" or "ex".
+	VmFlags []string
+}
+
+// ProcSMaps returns the memory mappings of the process along with their
+// memory usage.
+func (p Proc) ProcSMaps() ([]ProcSMap, error) {
+	f, err := os.Open(p.path("smaps"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	smaps, err := parseProcSMaps(f)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %s", f.Name(), err)
+	}
+
+	return smaps, nil
+}
+
+// ProcSMapsRollup returns the memory usage summed over all mappings of the
+// process. It is read from /proc/[pid]/smaps_rollup, which is available
+// since Linux 4.14, or computed from /proc/[pid]/smaps on older kernels.
+func (p Proc) ProcSMapsRollup() (SMapsMemory, error) {
+	f, err := os.Open(p.path("smaps_rollup"))
+	if os.IsNotExist(err) {
+		smaps, err := p.ProcSMaps()
+		if err != nil {
+			return SMapsMemory{}, err
+		}
+
+		var total SMapsMemory
+		for _, m := range smaps {
+			total.add(m.SMapsMemory)
+		}
+		return total, nil
+	}
+	if err != nil {
+		return SMapsMemory{}, err
+	}
+	defer f.Close()
+
+	smaps, err := parseProcSMaps(f)
+	if err != nil {
+		return SMapsMemory{}, fmt.Errorf("couldn't parse %s: %s", f.Name(), err)
+	}
+	if len(smaps) != 1 {
+		return SMapsMemory{}, fmt.Errorf("unexpected number of entries in %s: %d", f.Name(), len(smaps))
+	}
+
+	return smaps[0].SMapsMemory, nil
+}
+
+func (m *SMapsMemory) add(o SMapsMemory) {
+	m.Rss += o.Rss
+	m.Pss += o.Pss
+	m.SharedClean += o.SharedClean
+	m.SharedDirty += o.SharedDirty
+	m.PrivateClean += o.PrivateClean
+	m.PrivateDirty += o.PrivateDirty
+	m.Referenced += o.Referenced
+	m.Anonymous += o.Anonymous
+	m.AnonHugePages += o.AnonHugePages
+	m.Swap += o.Swap
+	m.SwapPss += o.SwapPss
+	m.Locked += o.Locked
+}
+
+func parseProcSMaps(r io.Reader) ([]ProcSMap, error) {
+	var (
+		smaps   = []ProcSMap{}
+		current *ProcSMap
+	)
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		// Attribute lines start with a key ending in a colon, mapping lines
+		// with an address range.
+		if !strings.HasSuffix(fields[0], ":") {
+			m, err := parseProcMap(line)
+			if err != nil {
+				return nil, err
+			}
+			smaps = append(smaps, ProcSMap{ProcMap: m})
+			current = &smaps[len(smaps)-1]
+			continue
+		}
+		if current == nil {
+			return nil, fmt.Errorf("attribute before first mapping: %q", line)
+		}
+
+		key := strings.TrimSuffix(fields[0], ":")
+		if key == "VmFlags" {
+			current.VmFlags = fields[1:]
+			continue
+		}
+
+		var v *uint64
+		switch key {
+		case "Size":
+			v = &current.Size
+		case "KernelPageSize":
+			v = &current.KernelPageSize
+		case "MMUPageSize":
+			v = &current.MMUPageSize
+		case "Rss":
+			v = &current.Rss
+		case "Pss":
+			v = &current.Pss
+		case "Shared_Clean":
+			v = &current.SharedClean
+		case "Shared_Dirty":
+			v = &current.SharedDirty
+		case "Private_Clean":
+			v = &current.PrivateClean
+		case "Private_Dirty":
+			v = &current.PrivateDirty
+		case "Referenced":
+			v = &current.Referenced
+		case "Anonymous":
+			v = &current.Anonymous
+		case "AnonHugePages":
+			v = &current.AnonHugePages
+		case "Swap":
+			v = &current.Swap
+		case "SwapPss":
+			v = &current.SwapPss
+		case "Locked":
+			v = &current.Locked
+		default:
+			continue
+		}
+
+		if len(fields) != 3 || fields[2] != "kB" {
+			return nil, fmt.Errorf("unexpected format for %s: %q", key, line)
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %s", key, err)
+		}
+		*v = kb * 1024
+	}
+
+	return smaps, s.Err()
+}
+
+// parseProcMap parses a single line of /proc/[pid]/maps, or a mapping line
+// of /proc/[pid]/smaps:
+//
+//	address           perms offset  dev   inode   pathname
+//	00400000-00452000 r-xp 00000000 08:02 173521  /usr/bin/dbus-daemon
+func parseProcMap(line string) (ProcMap, error) {
+	var (
+		m    ProcMap
+		rest = line
+	)
+
+	fields := make([]string, 5)
+	for i := range fields {
+		rest = strings.TrimLeft(rest, " ")
+		end := strings.IndexByte(rest, ' ')
+		if end < 0 {
+			end = len(rest)
+		}
+		fields[i], rest = rest[:end], rest[end:]
+		if fields[i] == "" {
+			return ProcMap{}, fmt.Errorf("invalid maps line, too few fields: %q", line)
+		}
+	}
+	m.Pathname = strings.TrimSpace(rest)
+
+	addrs := strings.Split(fields[0], "-")
+	if len(addrs) != 2 {
+		return ProcMap{}, fmt.Errorf("invalid address range %q", fields[0])
+	}
+	start, err := strconv.ParseUint(addrs[0], 16, 64)
+	if err != nil {
+		return ProcMap{}, fmt.Errorf("invalid start address %q: %s", addrs[0], err)
+	}
+	end, err := strconv.ParseUint(addrs[1], 16, 64)
+	if err != nil {
+		return ProcMap{}, fmt.Errorf("invalid end address %q: %s", addrs[1], err)
+	}
+	m.StartAddr, m.EndAddr = uintptr(start), uintptr(end)
+
+	perms := fields[1]
+	if len(perms) != 4 {
+		return ProcMap{}, fmt.Errorf("invalid permissions %q", perms)
+	}
+	m.Perms = ProcMapPermissions{
+		Read:    perms[0] == 'r',
+		Write:   perms[1] == 'w',
+		Execute: perms[2] == 'x',
+		Shared:  perms[3] == 's',
+	}
+
+	if m.Offset, err = strconv.ParseInt(fields[2], 16, 64); err != nil {
+		return ProcMap{}, fmt.Errorf("invalid offset %q: %s", fields[2], err)
+	}
+
+	dev := strings.Split(fields[3], ":")
+	if len(dev) != 2 {
+		return ProcMap{}, fmt.Errorf("invalid device %q", fields[3])
+	}
+	major, err := strconv.ParseUint(dev[0], 16, 32)
+	if err != nil {
+		return ProcMap{}, fmt.Errorf("invalid device major %q: %s", dev[0], err)
+	}
+	minor, err := strconv.ParseUint(dev[1], 16, 32)
+	if err != nil {
+		return ProcMap{}, fmt.Errorf("invalid device minor %q: %s", dev[1], err)
+	}
+	m.DevMajor, m.DevMinor = uint32(major), uint32(minor)
+
+	if m.Inode, err = strconv.ParseUint(fields[4], 10, 64); err != nil {
+		return ProcMap{}, fmt.Errorf("invalid inode %q: %s", fields[4], err)
+	}
+
+	return m, nil
+}
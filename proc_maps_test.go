@@ -0,0 +1,187 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProcMaps(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	maps, err := p.ProcMaps()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 8, len(maps); want != have {
+		t.Fatalf("want %d maps, have %d", want, have)
+	}
+
+	for i, want := range map[int]ProcMap{
+		0: {
+			StartAddr: 0x55680ae1e000,
+			EndAddr:   0x55680ae20000,
+			Perms:     ProcMapPermissions{Read: true},
+			DevMajor:  0xfd,
+			DevMinor:  0x01,
+			Inode:     47316994,
+			Pathname:  "/bin/cat",
+		},
+		1: {
+			StartAddr: 0x55680ae29000,
+			EndAddr:   0x55680ae2a000,
+			Perms:     ProcMapPermissions{Read: true, Write: true, Shared: true},
+			Offset:    0xa000,
+			DevMajor:  0xfd,
+			DevMinor:  0x01,
+			Inode:     47316994,
+			Pathname:  "/bin/cat",
+		},
+		5: {
+			StartAddr: 0x7fdf97600000,
+			EndAddr:   0x7fdf97601000,
+			Perms:     ProcMapPermissions{Read: true, Write: true},
+		},
+		7: {
+			StartAddr: 0xffffffffff600000,
+			EndAddr:   0xffffffffff601000,
+			Perms:     ProcMapPermissions{Execute: true},
+			Pathname:  "[vsyscall]",
+		},
+	} {
+		if have := maps[i]; want != have {
+			t.Errorf("map %d: want %+v, have %+v", i, want, have)
+		}
+	}
+}
+
+func TestParseProcMapInvalid(t *testing.T) {
+	for _, line := range []string{
+		"00400000-00452000 r-xp 00000000 08:02",
+		"00400000 r-xp 00000000 08:02 173521 /usr/bin/dbus-daemon",
+		"00400000-00452000 r-x 00000000 08:02 173521 /usr/bin/dbus-daemon",
+		"00400000-00452000 r-xp 00000000 0802 173521 /usr/bin/dbus-daemon",
+		"00400000-00452000 r-xp 00000000 08:02 x /usr/bin/dbus-daemon",
+	} {
+		if _, err := parseProcMap(line); err == nil {
+			t.Errorf("want error parsing %q, have none", line)
+		}
+	}
+}
+
+func TestProcSMaps(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	smaps, err := p.ProcSMaps()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 3, len(smaps); want != have {
+		t.Fatalf("want %d smaps, have %d", want, have)
+	}
+
+	want := ProcSMap{
+		ProcMap: ProcMap{
+			StartAddr: 0xc000000000,
+			EndAddr:   0xc000400000,
+			Perms:     ProcMapPermissions{Read: true, Write: true},
+		},
+		SMapsMemory: SMapsMemory{
+			Rss:           2564 * 1024,
+			Pss:           2564 * 1024,
+			PrivateClean:  20 * 1024,
+			PrivateDirty:  2544 * 1024,
+			Referenced:    2544 * 1024,
+			Anonymous:     2564 * 1024,
+			AnonHugePages: 2048 * 1024,
+			Swap:          12 * 1024,
+			SwapPss:       8 * 1024,
+		},
+		Size:           4096 * 1024,
+		KernelPageSize: 4 * 1024,
+		MMUPageSize:    4 * 1024,
+		VmFlags:        []string{"rd", "wr", "mr", "mw", "me", "ac", "sd"},
+	}
+	if have := smaps[2]; !reflect.DeepEqual(want, have) {
+		t.Errorf("want %+v, have %+v", want, have)
+	}
+
+	if want, have := uint64(1432*1024), smaps[0].Pss; want != have {
+		t.Errorf("want pss %d, have %d", want, have)
+	}
+	if want, have := "/bin/alertmanager", smaps[1].Pathname; want != have {
+		t.Errorf("want pathname %s, have %s", want, have)
+	}
+}
+
+func TestProcSMapsRollup(t *testing.T) {
+	for _, tt := range []struct {
+		pid  int
+		want SMapsMemory
+	}{
+		{
+			pid: 26231,
+			want: SMapsMemory{
+				Rss:           29948 * 1024,
+				Pss:           29944 * 1024,
+				SharedClean:   4 * 1024,
+				PrivateClean:  26432 * 1024,
+				PrivateDirty:  3512 * 1024,
+				Referenced:    29948 * 1024,
+				Anonymous:     3512 * 1024,
+				AnonHugePages: 2048 * 1024,
+				Swap:          12 * 1024,
+				SwapPss:       8 * 1024,
+			},
+		},
+		{
+			// No smaps_rollup, summed up from smaps.
+			pid: 26232,
+			want: SMapsMemory{
+				Rss:           (2864 + 6152 + 2564) * 1024,
+				Pss:           (1432 + 6152 + 2564) * 1024,
+				SharedClean:   2864 * 1024,
+				PrivateClean:  (6152 + 20) * 1024,
+				PrivateDirty:  2544 * 1024,
+				Referenced:    (2864 + 5308 + 2544) * 1024,
+				Anonymous:     2564 * 1024,
+				AnonHugePages: 2048 * 1024,
+				Swap:          12 * 1024,
+				SwapPss:       8 * 1024,
+			},
+		},
+	} {
+		p, err := FS("fixtures").NewProc(tt.pid)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		have, err := p.ProcSMapsRollup()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.want != have {
+			t.Errorf("%d: want %+v, have %+v", tt.pid, tt.want, have)
+		}
+	}
+}
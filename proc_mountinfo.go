@@ -0,0 +1,200 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// MountInfo is a mount of the mount namespace of a process, read from
+// /proc/[pid]/mountinfo.
+type MountInfo struct {
+	// A unique ID for the mount, which may be reused after it is unmounted.
+	MountID int
+	// The ID of the parent mount, or of the mount itself for the root of the
+	// mount tree.
+	ParentID int
+	// The major and minor number of the device holding the filesystem.
+	Major uint32
+	Minor uint32
+	// The path of the directory in the filesystem which forms the root of
+	// the mount.
+	Root string
+	// The mount point, relative to the root directory of the process.
+	MountPoint string
+	// The per-mount options, e.g. "rw" or "noatime". Options without a value
+	// map to the empty string.
+	Options map[string]string
+	// The optional fields, which describe the mount propagation, e.g.
+	// "shared" or "master" mapping to the peer group ID, or "unbindable"
+	// mapping to the empty string.
+	OptionalFields map[string]string
+	// The filesystem type, e.g. "ext4" or "nfs4".
+	FSType string
+	// The filesystem specific source, e.g. "/dev/sda1", or "none".
+	Source string
+	// The per-superblock options, e.g. "errors=remount-ro".
+	SuperOptions map[string]string
+}
+
+// MountUsage is the usage of a mounted filesystem, as reported by statfs(2).
+type MountUsage struct {
+	// The size of the filesystem, the free space, and the free space
+	// available to unprivileged users, in bytes.
+	Size      uint64
+	Free      uint64
+	Available uint64
+	// The total and free number of inodes.
+	Inodes     uint64
+	InodesFree uint64
+}
+
+// MountInfo retrieves the mounts of the mount namespace of the process.
+func (p Proc) MountInfo() ([]*MountInfo, error) {
+	f, err := os.Open(p.path("mountinfo"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	mounts, err := parseMountInfo(f)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %s", f.Name(), err)
+	}
+
+	return mounts, nil
+}
+
+func parseMountInfo(r io.Reader) ([]*MountInfo, error) {
+	mounts := []*MountInfo{}
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		if s.Text() == "" {
+			continue
+		}
+
+		m, err := parseMountInfoLine(s.Text())
+		if err != nil {
+			return nil, err
+		}
+		mounts = append(mounts, m)
+	}
+
+	return mounts, s.Err()
+}
+
+// parseMountInfoLine parses a single line of /proc/[pid]/mountinfo:
+//
+//	36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+//	(1)(2)(3)   (4)   (5)      (6)      (7)   (8) (9)   (10)         (11)
+//
+// The optional fields (7) are terminated by a single hyphen (8).
+func parseMountInfoLine(line string) (*MountInfo, error) {
+	fields := strings.Fields(line)
+
+	sep := -1
+	for i := 6; i < len(fields); i++ {
+		if fields[i] == "-" {
+			sep = i
+			break
+		}
+	}
+	if sep < 0 || len(fields) != sep+4 {
+		return nil, fmt.Errorf("unexpected mountinfo line %q", line)
+	}
+
+	m := &MountInfo{
+		Root:           unescapeMountField(fields[3]),
+		MountPoint:     unescapeMountField(fields[4]),
+		Options:        parseMountOptions(fields[5]),
+		OptionalFields: map[string]string{},
+		FSType:         fields[sep+1],
+		Source:         unescapeMountField(fields[sep+2]),
+		SuperOptions:   parseMountOptions(fields[sep+3]),
+	}
+
+	var err error
+	if m.MountID, err = strconv.Atoi(fields[0]); err != nil {
+		return nil, fmt.Errorf("invalid mount ID %q: %s", fields[0], err)
+	}
+	if m.ParentID, err = strconv.Atoi(fields[1]); err != nil {
+		return nil, fmt.Errorf("invalid parent ID %q: %s", fields[1], err)
+	}
+
+	dev := strings.Split(fields[2], ":")
+	if len(dev) != 2 {
+		return nil, fmt.Errorf("invalid device %q", fields[2])
+	}
+	major, err := strconv.ParseUint(dev[0], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid device major %q: %s", dev[0], err)
+	}
+	minor, err := strconv.ParseUint(dev[1], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid device minor %q: %s", dev[1], err)
+	}
+	m.Major, m.Minor = uint32(major), uint32(minor)
+
+	for _, f := range fields[6:sep] {
+		kv := strings.SplitN(f, ":", 2)
+		if len(kv) == 2 {
+			m.OptionalFields[kv[0]] = kv[1]
+		} else {
+			m.OptionalFields[kv[0]] = ""
+		}
+	}
+
+	return m, nil
+}
+
+// parseMountOptions parses a comma separated list of mount options.
+func parseMountOptions(s string) map[string]string {
+	options := map[string]string{}
+	for _, o := range strings.Split(s, ",") {
+		kv := strings.SplitN(o, "=", 2)
+		if len(kv) == 2 {
+			options[kv[0]] = kv[1]
+		} else {
+			options[kv[0]] = ""
+		}
+	}
+	return options
+}
+
+// unescapeMountField replaces the octal escapes of the kernel, e.g. "\040"
+// for a space, with the characters they represent.
+func unescapeMountField(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if c, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b = append(b, byte(c))
+				i += 3
+				continue
+			}
+		}
+		b = append(b, s[i])
+	}
+	return string(b)
+}
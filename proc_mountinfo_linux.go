@@ -0,0 +1,44 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import "syscall"
+
+// Usage calls statfs(2) on the mount point and returns the usage of the
+// mounted filesystem.
+//
+// The mount point is resolved relative to the root directory and in the
+// mount namespace of the calling process, so the result is only meaningful
+// for mounts of processes sharing both with it.
+func (m *MountInfo) Usage() (MountUsage, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(m.MountPoint, &st); err != nil {
+		return MountUsage{}, err
+	}
+
+	// Block counts are in units of the fragment size, which defaults to the
+	// block size on filesystems not setting it.
+	bsize := uint64(st.Frsize)
+	if bsize == 0 {
+		bsize = uint64(st.Bsize)
+	}
+
+	return MountUsage{
+		Size:       st.Blocks * bsize,
+		Free:       st.Bfree * bsize,
+		Available:  st.Bavail * bsize,
+		Inodes:     st.Files,
+		InodesFree: st.Ffree,
+	}, nil
+}
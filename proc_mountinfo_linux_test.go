@@ -0,0 +1,33 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import "testing"
+
+func TestMountInfoUsage(t *testing.T) {
+	m := &MountInfo{MountPoint: "."}
+
+	u, err := m.Usage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Size == 0 || u.Free > u.Size || u.Available > u.Free {
+		t.Errorf("unexpected usage %+v", u)
+	}
+
+	m = &MountInfo{MountPoint: "fixtures/nonexistent"}
+	if _, err := m.Usage(); err == nil {
+		t.Error("want error for non-existing mount point, have none")
+	}
+}
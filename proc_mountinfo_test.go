@@ -0,0 +1,123 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMountInfo(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mounts, err := p.MountInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 13, len(mounts); want != have {
+		t.Fatalf("want %d mounts, have %d", want, have)
+	}
+
+	for i, want := range map[int]*MountInfo{
+		3: {
+			MountID:        21,
+			ParentID:       0,
+			Major:          8,
+			Minor:          1,
+			Root:           "/",
+			MountPoint:     "/",
+			Options:        map[string]string{"rw": "", "relatime": ""},
+			OptionalFields: map[string]string{"shared": "1"},
+			FSType:         "ext4",
+			Source:         "/dev/sda1",
+			SuperOptions:   map[string]string{"rw": "", "errors": "remount-ro", "data": "ordered"},
+		},
+		9: {
+			MountID:        32,
+			ParentID:       25,
+			Major:          0,
+			Minor:          28,
+			Root:           "/docker/c0ffee",
+			MountPoint:     "/sys/fs/cgroup/blkio",
+			Options:        map[string]string{"rw": "", "nosuid": "", "nodev": "", "noexec": "", "relatime": ""},
+			OptionalFields: map[string]string{"shared": "16"},
+			FSType:         "cgroup",
+			Source:         "cgroup",
+			SuperOptions:   map[string]string{"rw": "", "blkio": ""},
+		},
+		11: {
+			MountID:        40,
+			ParentID:       21,
+			Major:          8,
+			Minor:          2,
+			Root:           "/",
+			MountPoint:     "/mnt/my disk",
+			Options:        map[string]string{"rw": "", "noatime": ""},
+			OptionalFields: map[string]string{"master": "3", "propagate_from": "2"},
+			FSType:         "ext4",
+			Source:         "/dev/sda2",
+			SuperOptions:   map[string]string{"rw": ""},
+		},
+		12: {
+			MountID:        41,
+			ParentID:       21,
+			Major:          0,
+			Minor:          50,
+			Root:           "/",
+			MountPoint:     "/run/user/1000",
+			Options:        map[string]string{"rw": "", "nosuid": "", "nodev": "", "relatime": ""},
+			OptionalFields: map[string]string{},
+			FSType:         "tmpfs",
+			Source:         "tmpfs",
+			SuperOptions:   map[string]string{"rw": "", "size": "1638400k", "mode": "700", "uid": "1000", "gid": "1000"},
+		},
+	} {
+		if have := mounts[i]; !reflect.DeepEqual(want, have) {
+			t.Errorf("mount %d: want %+v, have %+v", i, want, have)
+		}
+	}
+}
+
+func TestParseMountInfoLineInvalid(t *testing.T) {
+	for _, line := range []string{
+		"21 0 8:1 / / rw,relatime shared:1 ext4 /dev/sda1 rw",
+		"21 0 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1",
+		"x 0 8:1 / / rw,relatime - ext4 /dev/sda1 rw",
+		"21 x 8:1 / / rw,relatime - ext4 /dev/sda1 rw",
+		"21 0 81 / / rw,relatime - ext4 /dev/sda1 rw",
+		"21 0 8:x / / rw,relatime - ext4 /dev/sda1 rw",
+	} {
+		if _, err := parseMountInfoLine(line); err == nil {
+			t.Errorf("want error parsing %q, have none", line)
+		}
+	}
+}
+
+func TestUnescapeMountField(t *testing.T) {
+	for in, want := range map[string]string{
+		`/mnt/plain`:          "/mnt/plain",
+		`/mnt/my\040disk`:     "/mnt/my disk",
+		`/mnt/tab\011and\134`: "/mnt/tab\tand\\",
+		`/mnt/trailing\04`:    `/mnt/trailing\04`,
+		`/mnt/not\9octal`:     `/mnt/not\9octal`,
+	} {
+		if have := unescapeMountField(in); want != have {
+			t.Errorf("%s: want %q, have %q", in, want, have)
+		}
+	}
+}
@@ -16,27 +16,11 @@ package procfs
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 )
 
-// Originally, this USER_HZ value was dynamically retrieved via a sysconf call
-// which required cgo. However, that caused a lot of problems regarding
-// cross-compilation. Alternatives such as running a binary to determine the
-// value, or trying to derive it in some other way were all problematic.  After
-// much research it was determined that USER_HZ is actually hardcoded to 100 on
-// all Go-supported platforms as of the time of this writing. This is why we
-// decided to hardcode it here as well. It is not impossible that there could
-// be systems with exceptions, but they should be very exotic edge cases, and
-// in that case, the worst outcome will be two misreported metrics.
-//
-// See also the following discussions:
-//
-// - https://github.com/prometheus/node_exporter/issues/52
-// - https://github.com/prometheus/procfs/pull/2
-// - http://stackoverflow.com/questions/17410841/how-does-user-hz-solve-the-jiffy-scaling-issue
-const userHZ = 100
-
 // ProcStat provides status information about the process,
 // read from /proc/[pid]/stat.
 type ProcStat struct {
@@ -98,6 +82,51 @@ type ProcStat struct {
 	VSize int
 	// Resident set size in pages.
 	RSS int
+	// Soft limit of the resident set size in bytes.
+	RSSLimit uint64
+	// The address above which program text can run, and below which it
+	// can run.
+	StartCode uint64
+	EndCode   uint64
+	// The address of the start, i.e. bottom, of the stack.
+	StartStack uint64
+	// The current value of the stack pointer and instruction pointer, if the
+	// process is being traced. Zero otherwise.
+	KStkESP uint64
+	KStkEIP uint64
+	// Signal to be sent to the parent when the process dies.
+	ExitSignal int
+	// The CPU number the process last ran on.
+	Processor int
+	// The real-time scheduling priority, in the range 1 to 99 for processes
+	// scheduled under a real-time policy, or 0 otherwise.
+	RTPriority uint
+	// The scheduling policy, e.g. 0 for SCHED_OTHER.
+	Policy uint
+	// Aggregated block IO delays, i.e. the time spent waiting for IO,
+	// measured in clock ticks. Available since Linux 2.6.18.
+	DelayAcctBlkIOTicks uint64
+	// Amount of time spent running a virtual CPU for a guest operating
+	// system, measured in clock ticks. Available since Linux 2.6.24.
+	GuestTime uint
+	// Amount of guest time of the process's waited-for children, measured in
+	// clock ticks. Available since Linux 2.6.24.
+	CGuestTime int
+	// The addresses above and below which program initialized and
+	// uninitialized data are placed, and above which the heap can be
+	// expanded with brk(2). Available since Linux 3.3.
+	StartData uint64
+	EndData   uint64
+	StartBrk  uint64
+	// The addresses above and below which the program command-line arguments
+	// and environment are placed. Available since Linux 3.5.
+	ArgStart uint64
+	ArgEnd   uint64
+	EnvStart uint64
+	EnvEnd   uint64
+	// The exit status of the thread in the form reported by waitpid(2).
+	// Available since Linux 3.5.
+	ExitCode int
 
 	fs FS
 }
@@ -116,7 +145,8 @@ func (p Proc) NewStat() (ProcStat, error) {
 	}
 
 	var (
-		ignore int
+		ignore       int
+		ignoreUint64 uint64
 
 		s = ProcStat{PID: p.PID, fs: p.fs}
 		l = bytes.Index(data, []byte("("))
@@ -131,8 +161,9 @@ func (p Proc) NewStat() (ProcStat, error) {
 	}
 
 	s.Comm = string(data[l+1 : r])
+	buf := bytes.NewBuffer(data[r+2:])
 	_, err = fmt.Fscan(
-		bytes.NewBuffer(data[r+2:]),
+		buf,
 		&s.State,
 		&s.PPID,
 		&s.PGRP,
@@ -160,6 +191,43 @@ func (p Proc) NewStat() (ProcStat, error) {
 		return ProcStat{}, err
 	}
 
+	// The remaining fields were added over time, older kernels omit the
+	// trailing ones.
+	_, err = fmt.Fscan(
+		buf,
+		&s.RSSLimit,
+		&s.StartCode,
+		&s.EndCode,
+		&s.StartStack,
+		&s.KStkESP,
+		&s.KStkEIP,
+		&ignoreUint64, // signal, obsolete
+		&ignoreUint64, // blocked, obsolete
+		&ignoreUint64, // sigignore, obsolete
+		&ignoreUint64, // sigcatch, obsolete
+		&ignoreUint64, // wchan
+		&ignoreUint64, // nswap, not maintained
+		&ignoreUint64, // cnswap, not maintained
+		&s.ExitSignal,
+		&s.Processor,
+		&s.RTPriority,
+		&s.Policy,
+		&s.DelayAcctBlkIOTicks,
+		&s.GuestTime,
+		&s.CGuestTime,
+		&s.StartData,
+		&s.EndData,
+		&s.StartBrk,
+		&s.ArgStart,
+		&s.ArgEnd,
+		&s.EnvStart,
+		&s.EnvEnd,
+		&s.ExitCode,
+	)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return ProcStat{}, err
+	}
+
 	return s, nil
 }
 
@@ -170,7 +238,7 @@ func (s ProcStat) VirtualMemory() int {
 
 // ResidentMemory returns the resident memory size in bytes.
 func (s ProcStat) ResidentMemory() int {
-	return s.RSS * os.Getpagesize()
+	return s.RSS * int(s.fs.PageSize())
 }
 
 // StartTime returns the unix timestamp of the process in seconds.
@@ -179,10 +247,15 @@ func (s ProcStat) StartTime() (float64, error) {
 	if err != nil {
 		return 0, err
 	}
-	return float64(stat.BootTime) + (float64(s.Starttime) / userHZ), nil
+	return float64(stat.BootTime) + (float64(s.Starttime) / float64(s.fs.UserHZ())), nil
+}
+
+// BlkIODelay returns the time spent waiting for block IO in seconds.
+func (s ProcStat) BlkIODelay() float64 {
+	return float64(s.DelayAcctBlkIOTicks) / float64(s.fs.UserHZ())
 }
 
 // CPUTime returns the total CPU user and system time in seconds.
 func (s ProcStat) CPUTime() float64 {
-	return float64(s.UTime+s.STime) / userHZ
+	return float64(s.UTime+s.STime) / float64(s.fs.UserHZ())
 }
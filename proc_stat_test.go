@@ -13,10 +13,7 @@
 
 package procfs
 
-import (
-	"os"
-	"testing"
-)
+import "testing"
 
 func TestProcStat(t *testing.T) {
 	p, err := FS("fixtures").NewProc(26231)
@@ -47,6 +44,58 @@ func TestProcStat(t *testing.T) {
 	}
 }
 
+func TestProcStatTrailingFields(t *testing.T) {
+	s, err := testProcStat(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name string
+		want uint64
+		have uint64
+	}{
+		{name: "rss limit", want: 18446744073709551615, have: s.RSSLimit},
+		{name: "start code", want: 4194304, have: s.StartCode},
+		{name: "end code", want: 6294284, have: s.EndCode},
+		{name: "start stack", want: 140736914091744, have: s.StartStack},
+		{name: "exit signal", want: 17, have: uint64(s.ExitSignal)},
+		{name: "processor", want: 3, have: uint64(s.Processor)},
+		{name: "delayacct blkio ticks", want: 31, have: s.DelayAcctBlkIOTicks},
+		{name: "start data", want: 8391624, have: s.StartData},
+		{name: "start brk", want: 16420864, have: s.StartBrk},
+		{name: "arg start", want: 140736914093252, have: s.ArgStart},
+		{name: "env end", want: 140736914096107, have: s.EnvEnd},
+		{name: "exit code", want: 0, have: uint64(s.ExitCode)},
+	} {
+		if test.want != test.have {
+			t.Errorf("want %s %d, have %d", test.name, test.want, test.have)
+		}
+	}
+
+	if want, have := 0.31, s.BlkIODelay(); want != have {
+		t.Errorf("want blkio delay %f, have %f", want, have)
+	}
+}
+
+func TestProcStatOldKernel(t *testing.T) {
+	// Linux 2.6.18 stops after delayacct_blkio_ticks.
+	s, err := testProcStat(26234)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 2, s.Processor; want != have {
+		t.Errorf("want processor %d, have %d", want, have)
+	}
+	if want, have := uint64(7), s.DelayAcctBlkIOTicks; want != have {
+		t.Errorf("want delayacct blkio ticks %d, have %d", want, have)
+	}
+	if want, have := uint64(0), s.ArgStart; want != have {
+		t.Errorf("want arg start %d, have %d", want, have)
+	}
+}
+
 func TestProcStatComm(t *testing.T) {
 	s1, err := testProcStat(26231)
 	if err != nil {
@@ -82,7 +131,7 @@ func TestProcStatResidentMemory(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if want, have := 1981*os.Getpagesize(), s.ResidentMemory(); want != have {
+	if want, have := 1981*4096, s.ResidentMemory(); want != have {
 		t.Errorf("want resident memory %d, have %d", want, have)
 	}
 }
@@ -0,0 +1,543 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ProcStatus provides status information about the process,
+// read from /proc/[pid]/status. Memory sizes are in bytes.
+type ProcStatus struct {
+	// The process ID.
+	PID int
+	// The filename of the executable.
+	Name string
+	// The process state, e.g. "S (sleeping)".
+	State string
+	// The thread group ID, i.e. the PID of the process a thread belongs to.
+	TGID int
+	// The PID of the parent of this process.
+	PPID int
+	// The PID of the process tracing this process, or 0 if not traced.
+	TracerPID int
+	// The real, effective, saved set and filesystem UIDs.
+	UIDs [4]uint64
+	// The real, effective, saved set and filesystem GIDs.
+	GIDs [4]uint64
+	// The supplementary group IDs.
+	Groups []uint64
+	// The thread group IDs and PIDs in each PID namespace the process is a
+	// member of, from the outermost to the innermost. Available since
+	// Linux 4.1.
+	NSTGID []int
+	NSPID  []int
+
+	// Peak virtual memory size.
+	VmPeak uint64
+	// Virtual memory size.
+	VmSize uint64
+	// Locked memory size.
+	VmLck uint64
+	// Pinned memory size.
+	VmPin uint64
+	// Peak resident set size.
+	VmHWM uint64
+	// Resident set size, the sum of RssAnon, RssFile and RssShmem.
+	VmRSS uint64
+	// Size of resident anonymous memory.
+	RssAnon uint64
+	// Size of resident file mappings.
+	RssFile uint64
+	// Size of resident shared memory.
+	RssShmem uint64
+	// Size of the data segment.
+	VmData uint64
+	// Size of the stack segment.
+	VmStk uint64
+	// Size of the text segment.
+	VmExe uint64
+	// Size of shared library code.
+	VmLib uint64
+	// Size of the page table entries.
+	VmPTE uint64
+	// Amount of swap used by anonymous private data.
+	VmSwap uint64
+	// Size of hugetlb memory portions.
+	HugetlbPages uint64
+
+	// Number of threads in the process.
+	Threads uint64
+
+	// Signals pending for the thread and for the process as a whole.
+	SigPnd SignalSet
+	ShdPnd SignalSet
+	// Signals being blocked, ignored and caught.
+	SigBlk SignalSet
+	SigIgn SignalSet
+	SigCgt SignalSet
+
+	// Inheritable, permitted, effective, bounding and ambient capabilities.
+	// CapAmb is available since Linux 4.3.
+	CapInh Capabilities
+	CapPrm Capabilities
+	CapEff Capabilities
+	CapBnd Capabilities
+	CapAmb Capabilities
+
+	// Whether the no_new_privs bit of the thread is set.
+	NoNewPrivs bool
+	// Seccomp mode of the process: 0 for disabled, 1 for strict and 2 for
+	// filter mode.
+	Seccomp int
+
+	// CPUs and memory nodes the process may run on, e.g. "0-3,8".
+	CpusAllowedList string
+	MemsAllowedList string
+
+	// Number of voluntary context switches.
+	VoluntaryCtxtSwitches uint64
+	// Number of involuntary context switches.
+	NonVoluntaryCtxtSwitches uint64
+}
+
+// NewStatus returns the current status information of the process.
+func (p Proc) NewStatus() (ProcStatus, error) {
+	f, err := os.Open(p.path("status"))
+	if err != nil {
+		return ProcStatus{}, err
+	}
+	defer f.Close()
+
+	s, err := parseProcStatus(f)
+	if err != nil {
+		return ProcStatus{}, fmt.Errorf("couldn't parse %s: %s", f.Name(), err)
+	}
+
+	return s, nil
+}
+
+// CPUsAllowed returns the CPUs the process may run on.
+func (s ProcStatus) CPUsAllowed() ([]uint64, error) {
+	return parseCPUList(s.CpusAllowedList)
+}
+
+func parseProcStatus(r io.Reader) (ProcStatus, error) {
+	var s ProcStatus
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		kv := strings.SplitN(sc.Text(), ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		k, v := kv[0], strings.TrimSpace(kv[1])
+
+		var err error
+		switch k {
+		case "Name":
+			s.Name = v
+		case "State":
+			s.State = v
+		case "Tgid":
+			s.TGID, err = strconv.Atoi(v)
+		case "Pid":
+			s.PID, err = strconv.Atoi(v)
+		case "PPid":
+			s.PPID, err = strconv.Atoi(v)
+		case "TracerPid":
+			s.TracerPID, err = strconv.Atoi(v)
+		case "Uid":
+			err = parseStatusIDs(v, &s.UIDs)
+		case "Gid":
+			err = parseStatusIDs(v, &s.GIDs)
+		case "Groups":
+			s.Groups, err = parseStatusUints(v)
+		case "NStgid":
+			s.NSTGID, err = parseStatusInts(v)
+		case "NSpid":
+			s.NSPID, err = parseStatusInts(v)
+		case "VmPeak":
+			s.VmPeak, err = parseStatusBytes(v)
+		case "VmSize":
+			s.VmSize, err = parseStatusBytes(v)
+		case "VmLck":
+			s.VmLck, err = parseStatusBytes(v)
+		case "VmPin":
+			s.VmPin, err = parseStatusBytes(v)
+		case "VmHWM":
+			s.VmHWM, err = parseStatusBytes(v)
+		case "VmRSS":
+			s.VmRSS, err = parseStatusBytes(v)
+		case "RssAnon":
+			s.RssAnon, err = parseStatusBytes(v)
+		case "RssFile":
+			s.RssFile, err = parseStatusBytes(v)
+		case "RssShmem":
+			s.RssShmem, err = parseStatusBytes(v)
+		case "VmData":
+			s.VmData, err = parseStatusBytes(v)
+		case "VmStk":
+			s.VmStk, err = parseStatusBytes(v)
+		case "VmExe":
+			s.VmExe, err = parseStatusBytes(v)
+		case "VmLib":
+			s.VmLib, err = parseStatusBytes(v)
+		case "VmPTE":
+			s.VmPTE, err = parseStatusBytes(v)
+		case "VmSwap":
+			s.VmSwap, err = parseStatusBytes(v)
+		case "HugetlbPages":
+			s.HugetlbPages, err = parseStatusBytes(v)
+		case "Threads":
+			s.Threads, err = strconv.ParseUint(v, 10, 64)
+		case "SigPnd":
+			err = parseStatusMask(v, (*uint64)(&s.SigPnd))
+		case "ShdPnd":
+			err = parseStatusMask(v, (*uint64)(&s.ShdPnd))
+		case "SigBlk":
+			err = parseStatusMask(v, (*uint64)(&s.SigBlk))
+		case "SigIgn":
+			err = parseStatusMask(v, (*uint64)(&s.SigIgn))
+		case "SigCgt":
+			err = parseStatusMask(v, (*uint64)(&s.SigCgt))
+		case "CapInh":
+			err = parseStatusMask(v, (*uint64)(&s.CapInh))
+		case "CapPrm":
+			err = parseStatusMask(v, (*uint64)(&s.CapPrm))
+		case "CapEff":
+			err = parseStatusMask(v, (*uint64)(&s.CapEff))
+		case "CapBnd":
+			err = parseStatusMask(v, (*uint64)(&s.CapBnd))
+		case "CapAmb":
+			err = parseStatusMask(v, (*uint64)(&s.CapAmb))
+		case "NoNewPrivs":
+			s.NoNewPrivs = v == "1"
+		case "Seccomp":
+			s.Seccomp, err = strconv.Atoi(v)
+		case "Cpus_allowed_list":
+			s.CpusAllowedList = v
+		case "Mems_allowed_list":
+			s.MemsAllowedList = v
+		case "voluntary_ctxt_switches":
+			s.VoluntaryCtxtSwitches, err = strconv.ParseUint(v, 10, 64)
+		case "nonvoluntary_ctxt_switches":
+			s.NonVoluntaryCtxtSwitches, err = strconv.ParseUint(v, 10, 64)
+		}
+		if err != nil {
+			return ProcStatus{}, fmt.Errorf("invalid value for %s %q: %s", k, v, err)
+		}
+	}
+
+	return s, sc.Err()
+}
+
+// parseStatusBytes parses a size in kB into bytes.
+func parseStatusBytes(v string) (uint64, error) {
+	n, err := strconv.ParseUint(strings.TrimSuffix(v, " kB"), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return n * 1024, nil
+}
+
+func parseStatusIDs(v string, ids *[4]uint64) error {
+	us, err := parseStatusUints(v)
+	if err != nil {
+		return err
+	}
+	if len(us) != len(ids) {
+		return fmt.Errorf("expected %d IDs, got %d", len(ids), len(us))
+	}
+	copy(ids[:], us)
+	return nil
+}
+
+func parseStatusUints(v string) ([]uint64, error) {
+	fields := strings.Fields(v)
+	us := make([]uint64, 0, len(fields))
+	for _, f := range fields {
+		u, err := strconv.ParseUint(f, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		us = append(us, u)
+	}
+	return us, nil
+}
+
+func parseStatusInts(v string) ([]int, error) {
+	fields := strings.Fields(v)
+	is := make([]int, 0, len(fields))
+	for _, f := range fields {
+		i, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, err
+		}
+		is = append(is, i)
+	}
+	return is, nil
+}
+
+func parseStatusMask(v string, mask *uint64) error {
+	m, err := strconv.ParseUint(v, 16, 64)
+	if err != nil {
+		return err
+	}
+	*mask = m
+	return nil
+}
+
+// parseCPUList parses a list of CPUs in the kernel's list format, e.g.
+// "0-3,8,10-11".
+func parseCPUList(list string) ([]uint64, error) {
+	var cpus []uint64
+	if list == "" {
+		return cpus, nil
+	}
+
+	for _, r := range strings.Split(list, ",") {
+		bounds := strings.SplitN(r, "-", 2)
+		first, err := strconv.ParseUint(bounds[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CPU list %q: %s", list, err)
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.ParseUint(bounds[1], 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid CPU list %q: %s", list, err)
+			}
+		}
+		if last < first {
+			return nil, fmt.Errorf("invalid CPU list %q: descending range %s", list, r)
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+
+	return cpus, nil
+}
+
+// Capability is a Linux capability, as described in capabilities(7).
+type Capability uint
+
+// Linux capabilities, see include/uapi/linux/capability.h in the kernel
+// sources.
+const (
+	CapChown Capability = iota
+	CapDacOverride
+	CapDacReadSearch
+	CapFowner
+	CapFsetid
+	CapKill
+	CapSetgid
+	CapSetuid
+	CapSetpcap
+	CapLinuxImmutable
+	CapNetBindService
+	CapNetBroadcast
+	CapNetAdmin
+	CapNetRaw
+	CapIpcLock
+	CapIpcOwner
+	CapSysModule
+	CapSysRawio
+	CapSysChroot
+	CapSysPtrace
+	CapSysPacct
+	CapSysAdmin
+	CapSysBoot
+	CapSysNice
+	CapSysResource
+	CapSysTime
+	CapSysTtyConfig
+	CapMknod
+	CapLease
+	CapAuditWrite
+	CapAuditControl
+	CapSetfcap
+	CapMacOverride
+	CapMacAdmin
+	CapSyslog
+	CapWakeAlarm
+	CapBlockSuspend
+	CapAuditRead
+	CapPerfmon
+	CapBpf
+	CapCheckpointRestore
+)
+
+var capabilityNames = []string{
+	"CAP_CHOWN",
+	"CAP_DAC_OVERRIDE",
+	"CAP_DAC_READ_SEARCH",
+	"CAP_FOWNER",
+	"CAP_FSETID",
+	"CAP_KILL",
+	"CAP_SETGID",
+	"CAP_SETUID",
+	"CAP_SETPCAP",
+	"CAP_LINUX_IMMUTABLE",
+	"CAP_NET_BIND_SERVICE",
+	"CAP_NET_BROADCAST",
+	"CAP_NET_ADMIN",
+	"CAP_NET_RAW",
+	"CAP_IPC_LOCK",
+	"CAP_IPC_OWNER",
+	"CAP_SYS_MODULE",
+	"CAP_SYS_RAWIO",
+	"CAP_SYS_CHROOT",
+	"CAP_SYS_PTRACE",
+	"CAP_SYS_PACCT",
+	"CAP_SYS_ADMIN",
+	"CAP_SYS_BOOT",
+	"CAP_SYS_NICE",
+	"CAP_SYS_RESOURCE",
+	"CAP_SYS_TIME",
+	"CAP_SYS_TTY_CONFIG",
+	"CAP_MKNOD",
+	"CAP_LEASE",
+	"CAP_AUDIT_WRITE",
+	"CAP_AUDIT_CONTROL",
+	"CAP_SETFCAP",
+	"CAP_MAC_OVERRIDE",
+	"CAP_MAC_ADMIN",
+	"CAP_SYSLOG",
+	"CAP_WAKE_ALARM",
+	"CAP_BLOCK_SUSPEND",
+	"CAP_AUDIT_READ",
+	"CAP_PERFMON",
+	"CAP_BPF",
+	"CAP_CHECKPOINT_RESTORE",
+}
+
+func (c Capability) String() string {
+	if int(c) < len(capabilityNames) {
+		return capabilityNames[c]
+	}
+	return fmt.Sprintf("CAP_%d", uint(c))
+}
+
+// Capabilities is a set of capabilities, decoded from one of the Cap*
+// fields of /proc/[pid]/status.
+type Capabilities uint64
+
+// Has returns whether the set contains the capability.
+func (cs Capabilities) Has(c Capability) bool {
+	return c < 64 && cs&(1<<c) != 0
+}
+
+// Capabilities returns the capabilities in the set in ascending order.
+func (cs Capabilities) Capabilities() []Capability {
+	caps := []Capability{}
+	for c := Capability(0); c < 64; c++ {
+		if cs.Has(c) {
+			caps = append(caps, c)
+		}
+	}
+	return caps
+}
+
+// Names returns the names of the capabilities in the set, e.g.
+// "CAP_NET_ADMIN". Capabilities unknown to this package are named by their
+// number, e.g. "CAP_41".
+func (cs Capabilities) Names() []string {
+	caps := cs.Capabilities()
+	names := make([]string, 0, len(caps))
+	for _, c := range caps {
+		names = append(names, c.String())
+	}
+	return names
+}
+
+var signalNames = []string{
+	1:  "SIGHUP",
+	2:  "SIGINT",
+	3:  "SIGQUIT",
+	4:  "SIGILL",
+	5:  "SIGTRAP",
+	6:  "SIGABRT",
+	7:  "SIGBUS",
+	8:  "SIGFPE",
+	9:  "SIGKILL",
+	10: "SIGUSR1",
+	11: "SIGSEGV",
+	12: "SIGUSR2",
+	13: "SIGPIPE",
+	14: "SIGALRM",
+	15: "SIGTERM",
+	16: "SIGSTKFLT",
+	17: "SIGCHLD",
+	18: "SIGCONT",
+	19: "SIGSTOP",
+	20: "SIGTSTP",
+	21: "SIGTTIN",
+	22: "SIGTTOU",
+	23: "SIGURG",
+	24: "SIGXCPU",
+	25: "SIGXFSZ",
+	26: "SIGVTALRM",
+	27: "SIGPROF",
+	28: "SIGWINCH",
+	29: "SIGIO",
+	30: "SIGPWR",
+	31: "SIGSYS",
+}
+
+// SignalSet is a set of signals, decoded from one of the signal mask fields
+// of /proc/[pid]/status. Bit n-1 of the mask is set if signal n is in the
+// set. Signal numbers and names follow the generic Linux ABI, as used on
+// x86 and ARM.
+type SignalSet uint64
+
+// Has returns whether the set contains the signal with the given number.
+func (ss SignalSet) Has(signal int) bool {
+	return signal >= 1 && signal <= 64 && ss&(1<<uint(signal-1)) != 0
+}
+
+// Signals returns the numbers of the signals in the set in ascending order.
+func (ss SignalSet) Signals() []int {
+	signals := []int{}
+	for s := 1; s <= 64; s++ {
+		if ss.Has(s) {
+			signals = append(signals, s)
+		}
+	}
+	return signals
+}
+
+// Names returns the names of the signals in the set, e.g. "SIGTERM".
+// Real-time signals are named relative to SIGRTMIN, which is signal 32 in
+// the kernel, e.g. "SIGRTMIN+2".
+func (ss SignalSet) Names() []string {
+	signals := ss.Signals()
+	names := make([]string, 0, len(signals))
+	for _, s := range signals {
+		switch {
+		case s < len(signalNames):
+			names = append(names, signalNames[s])
+		case s == 32:
+			names = append(names, "SIGRTMIN")
+		default:
+			names = append(names, fmt.Sprintf("SIGRTMIN+%d", s-32))
+		}
+	}
+	return names
+}
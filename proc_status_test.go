@@ -0,0 +1,143 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProcStatus(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := p.NewStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name string
+		want interface{}
+		have interface{}
+	}{
+		{name: "pid", want: 26231, have: s.PID},
+		{name: "name", want: "vim", have: s.Name},
+		{name: "state", want: "S (sleeping)", have: s.State},
+		{name: "ppid", want: 1, have: s.PPID},
+		{name: "uids", want: [4]uint64{1000, 1000, 1000, 0}, have: s.UIDs},
+		{name: "gids", want: [4]uint64{1001, 1001, 1001, 0}, have: s.GIDs},
+		{name: "groups", want: []uint64{4, 24, 27, 1001}, have: s.Groups},
+		{name: "nstgid", want: []int{26231, 12}, have: s.NSTGID},
+		{name: "nspid", want: []int{26231, 12}, have: s.NSPID},
+		{name: "vmhwm", want: uint64(8028 * 1024), have: s.VmHWM},
+		{name: "vmrss", want: uint64(6716 * 1024), have: s.VmRSS},
+		{name: "vmswap", want: uint64(4 * 1024), have: s.VmSwap},
+		{name: "threads", want: uint64(1), have: s.Threads},
+		{name: "no new privs", want: true, have: s.NoNewPrivs},
+		{name: "seccomp", want: 2, have: s.Seccomp},
+		{name: "cpus allowed list", want: "0-3,6", have: s.CpusAllowedList},
+		{name: "voluntary ctxt switches", want: uint64(4742839), have: s.VoluntaryCtxtSwitches},
+		{name: "nonvoluntary ctxt switches", want: uint64(1727500), have: s.NonVoluntaryCtxtSwitches},
+	} {
+		if !reflect.DeepEqual(test.want, test.have) {
+			t.Errorf("want %s %v, have %v", test.name, test.want, test.have)
+		}
+	}
+
+	cpus, err := s.CPUsAllowed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint64{0, 1, 2, 3, 6}; !reflect.DeepEqual(want, cpus) {
+		t.Errorf("want allowed cpus %v, have %v", want, cpus)
+	}
+
+	if want, have := []string{"SIGPIPE"}, s.SigIgn.Names(); !reflect.DeepEqual(want, have) {
+		t.Errorf("want ignored signals %v, have %v", want, have)
+	}
+	wantCaught := []string{
+		"SIGHUP", "SIGINT", "SIGABRT", "SIGUSR1", "SIGSEGV", "SIGALRM",
+		"SIGTERM", "SIGCHLD", "SIGPWR", "SIGRTMIN", "SIGRTMIN+1",
+	}
+	if have := s.SigCgt.Names(); !reflect.DeepEqual(wantCaught, have) {
+		t.Errorf("want caught signals %v, have %v", wantCaught, have)
+	}
+	if !s.SigCgt.Has(15) || s.SigCgt.Has(9) {
+		t.Errorf("want SIGTERM and not SIGKILL to be caught, have %v", s.SigCgt.Signals())
+	}
+
+	if have := s.CapEff.Names(); len(have) != 0 {
+		t.Errorf("want no effective capabilities, have %v", have)
+	}
+	if want, have := 38, len(s.CapBnd.Capabilities()); want != have {
+		t.Errorf("want %d bounding capabilities, have %d", want, have)
+	}
+	if !s.CapBnd.Has(CapAuditRead) || s.CapBnd.Has(CapPerfmon) {
+		t.Errorf("want CAP_AUDIT_READ and not CAP_PERFMON in bounding set, have %v", s.CapBnd.Names())
+	}
+}
+
+func TestCapabilitiesNames(t *testing.T) {
+	cs := Capabilities(1<<CapNetBindService | 1<<CapSysAdmin | 1<<45)
+
+	want := []string{"CAP_NET_BIND_SERVICE", "CAP_SYS_ADMIN", "CAP_45"}
+	if have := cs.Names(); !reflect.DeepEqual(want, have) {
+		t.Errorf("want capabilities %v, have %v", want, have)
+	}
+}
+
+func TestParseProcStatusInvalid(t *testing.T) {
+	for _, in := range []string{
+		"Pid:\tx\n",
+		"Uid:\t1000\t1000\n",
+		"VmRSS:\t   x kB\n",
+		"SigCgt:\tzzzz\n",
+	} {
+		if _, err := parseProcStatus(strings.NewReader(in)); err == nil {
+			t.Errorf("want error parsing %q, have none", in)
+		}
+	}
+}
+
+func TestParseCPUList(t *testing.T) {
+	for _, tt := range []struct {
+		list string
+		want []uint64
+		err  bool
+	}{
+		{list: "", want: nil},
+		{list: "0", want: []uint64{0}},
+		{list: "0-2,5,7-8", want: []uint64{0, 1, 2, 5, 7, 8}},
+		{list: "3-1", err: true},
+		{list: "a", err: true},
+	} {
+		have, err := parseCPUList(tt.list)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%q: want error, have none", tt.list)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tt.want, have) {
+			t.Errorf("%q: want %v, have %v", tt.list, tt.want, have)
+		}
+	}
+}
@@ -14,6 +14,7 @@
 package procfs
 
 import (
+	"os"
 	"reflect"
 	"sort"
 	"testing"
@@ -49,6 +50,70 @@ func TestAllProcs(t *testing.T) {
 	}
 }
 
+func TestAllThreads(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	threads, err := p.AllThreads()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Sort(threads)
+
+	for i, tt := range []struct {
+		tid   int
+		comm  string
+		utime uint
+		rchar uint64
+	}{
+		{tid: 26231, comm: "vim", utime: 1677, rchar: 750339},
+		{tid: 26232, comm: "gc worker", utime: 4012, rchar: 4096},
+	} {
+		if len(threads) <= i {
+			t.Fatalf("want thread %d, have %d threads", tt.tid, len(threads))
+		}
+		thread := threads[i]
+		if want, have := tt.tid, thread.PID; want != have {
+			t.Errorf("want thread %d, have %d", want, have)
+		}
+
+		comm, err := thread.Comm()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, have := tt.comm, comm; want != have {
+			t.Errorf("want comm %s, have %s", want, have)
+		}
+
+		stat, err := thread.NewStat()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, have := tt.comm, stat.Comm; want != have {
+			t.Errorf("want stat comm %s, have %s", want, have)
+		}
+		if want, have := tt.utime, stat.UTime; want != have {
+			t.Errorf("want utime %d, have %d", want, have)
+		}
+
+		io, err := thread.NewIO()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, have := tt.rchar, io.RChar; want != have {
+			t.Errorf("want rchar %d, have %d", want, have)
+		}
+	}
+
+	// A thread which exited after the enumeration.
+	exited := Proc{PID: 26233, fs: FS("fixtures"), tgid: 26231}
+	if _, err := exited.NewStat(); !os.IsNotExist(err) {
+		t.Errorf("want not exist error for exited thread, have %v", err)
+	}
+}
+
 func TestCmdLine(t *testing.T) {
 	for _, tt := range []struct {
 		process int
@@ -116,6 +181,82 @@ func TestExecutable(t *testing.T) {
 	}
 }
 
+func TestCwd(t *testing.T) {
+	for _, tt := range []struct {
+		process int
+		want    string
+	}{
+		{process: 26231, want: "/usr/bin"},
+		{process: 26232, want: ""},
+	} {
+		p, err := FS("fixtures").NewProc(tt.process)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wd, err := p.Cwd()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tt.want, wd) {
+			t.Errorf("want absolute path to cwd %v, have %v", tt.want, wd)
+		}
+	}
+}
+
+func TestRootDir(t *testing.T) {
+	for _, tt := range []struct {
+		process int
+		want    string
+	}{
+		{process: 26231, want: "/"},
+		{process: 26232, want: ""},
+	} {
+		p, err := FS("fixtures").NewProc(tt.process)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rdir, err := p.RootDir()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tt.want, rdir) {
+			t.Errorf("want absolute path to rootdir %v, have %v", tt.want, rdir)
+		}
+	}
+}
+
+func TestEnviron(t *testing.T) {
+	for _, tt := range []struct {
+		process int
+		want    map[string]string
+	}{
+		{
+			process: 26231,
+			want: map[string]string{
+				"PATH":       "/usr/local/bin:/usr/bin",
+				"HOME":       "/home/user",
+				"DEPLOYMENT": "prod-eu-1",
+				"EMPTY":      "",
+				"OPTS":       "a=b",
+				"NOVALUE":    "",
+			},
+		},
+		{process: 26232, want: map[string]string{}},
+	} {
+		p, err := FS("fixtures").NewProc(tt.process)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err := p.Environ()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tt.want, env) {
+			t.Errorf("want environ %v, have %v", tt.want, env)
+		}
+	}
+}
+
 func TestFileDescriptors(t *testing.T) {
 	p1, err := FS("fixtures").NewProc(26231)
 	if err != nil {
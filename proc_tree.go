@@ -0,0 +1,331 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProcTree is a snapshot of the hierarchy of processes.
+type ProcTree struct {
+	nodes map[int]*ProcTreeNode
+	roots []*ProcTreeNode
+}
+
+// ProcTreeNode is a process in a ProcTree.
+type ProcTreeNode struct {
+	Proc
+
+	// The status information of the process, read while building the tree.
+	Stat ProcStat
+	// The IO statistics of the process, or nil if they can't be read, e.g.
+	// because of missing permissions.
+	IO *ProcIO
+	// The parent process, or nil for the roots of the tree.
+	Parent *ProcTreeNode
+	// The child processes, ordered by PID.
+	Children []*ProcTreeNode
+}
+
+// ProcTreeUsage is the resource usage summed up over the processes of a
+// subtree. Resources of processes which already exited, like those included
+// in ProcStat.CUTime, are not accounted.
+type ProcTreeUsage struct {
+	// The number of processes.
+	Procs int
+	// The user and system CPU time in seconds.
+	CPUTime float64
+	// The resident memory size in bytes.
+	ResidentMemory int
+	// The IO of the processes whose IO statistics can be read.
+	RChar      uint64
+	WChar      uint64
+	ReadBytes  uint64
+	WriteBytes uint64
+}
+
+// NewProcTree returns a snapshot of the hierarchy of all processes under
+// /proc.
+func NewProcTree() (*ProcTree, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+	return fs.NewProcTree()
+}
+
+// NewProcTree returns a snapshot of the hierarchy of all processes.
+//
+// The parent of a process is taken from the children lists of
+// /proc/[pid]/task/[tid]/children where available, which requires Linux 3.5
+// built with CONFIG_PROC_CHILDREN, and from the PPID of the process
+// otherwise.
+//
+// Processes which appear or exit during the scan are left out, so that the
+// tree only contains processes whose status could be read. The children of
+// processes which exited become roots, see Orphans. If some processes can't
+// be read, the tree of all other processes is returned along with a
+// ProcErrors error.
+func (fs FS) NewProcTree() (*ProcTree, error) {
+	procs, err := fs.AllProcs()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		t    = &ProcTree{nodes: map[int]*ProcTreeNode{}, roots: []*ProcTreeNode{}}
+		errs = ProcErrors{}
+	)
+	for _, p := range procs {
+		stat, err := p.NewStat()
+		if err != nil {
+			if !os.IsNotExist(err) {
+				errs[p.PID] = err
+			}
+			continue
+		}
+
+		n := &ProcTreeNode{Proc: p, Stat: stat, Children: []*ProcTreeNode{}}
+		if io, err := p.NewIO(); err == nil {
+			n.IO = &io
+		}
+		t.nodes[p.PID] = n
+	}
+
+	t.link()
+
+	if len(errs) > 0 {
+		return t, errs
+	}
+	return t, nil
+}
+
+// link sets the parents and children of the nodes of the tree.
+func (t *ProcTree) link() {
+	// Collect the processes claiming to be the parent of each process.
+	claims := map[int][]int{}
+	for pid, n := range t.nodes {
+		children, err := n.Proc.Children()
+		if err != nil {
+			continue
+		}
+		for _, c := range children {
+			claims[c] = append(claims[c], pid)
+		}
+	}
+
+	parents := make(map[int]int, len(t.nodes))
+	for pid, n := range t.nodes {
+		parents[pid] = resolveParent(n.Stat.PPID, claims[pid])
+	}
+
+	for pid, n := range t.nodes {
+		parent, ok := t.nodes[parents[pid]]
+		if !ok || createsCycle(parents, pid) {
+			t.roots = append(t.roots, n)
+			continue
+		}
+		n.Parent = parent
+		parent.Children = append(parent.Children, n)
+	}
+
+	sortProcTreeNodes(t.roots)
+	for _, n := range t.nodes {
+		sortProcTreeNodes(n.Children)
+	}
+}
+
+// resolveParent returns the parent of a process, given its PPID and the
+// processes listing it as their child. These may differ if the process was
+// reparented during the scan. A listing parent matching the PPID is
+// preferred, then the lowest listing PID, for a stable result.
+func resolveParent(ppid int, claims []int) int {
+	if len(claims) == 0 {
+		return ppid
+	}
+
+	parent := claims[0]
+	for _, c := range claims {
+		if c == ppid {
+			return c
+		}
+		if c < parent {
+			parent = c
+		}
+	}
+	return parent
+}
+
+// createsCycle returns whether linking the process to its parent would
+// create a cycle, which can only happen if PIDs were reused during the scan.
+func createsCycle(parents map[int]int, pid int) bool {
+	parent := parents[pid]
+	for i := 0; i < len(parents); i++ {
+		if parent == pid {
+			return true
+		}
+		next, ok := parents[parent]
+		if !ok {
+			return false
+		}
+		parent = next
+	}
+	return true
+}
+
+// Get returns the process with the given PID, or nil if it is not part of
+// the tree.
+func (t *ProcTree) Get(pid int) *ProcTreeNode {
+	return t.nodes[pid]
+}
+
+// Len returns the number of processes in the tree.
+func (t *ProcTree) Len() int {
+	return len(t.nodes)
+}
+
+// Roots returns the processes without parent in the tree, ordered by PID.
+// These are the processes with PPID 0, like init and kthreadd, and orphans.
+func (t *ProcTree) Roots() []*ProcTreeNode {
+	return t.roots
+}
+
+// Orphans returns the processes whose parent is not part of the tree,
+// ordered by PID. The parent may have exited during the scan, or be outside
+// of the PID namespace of the FS.
+func (t *ProcTree) Orphans() []*ProcTreeNode {
+	orphans := []*ProcTreeNode{}
+	for _, n := range t.roots {
+		if n.Orphan() {
+			orphans = append(orphans, n)
+		}
+	}
+	return orphans
+}
+
+// Zombies returns the processes which exited but were not yet waited for by
+// their parent, ordered by PID.
+func (t *ProcTree) Zombies() []*ProcTreeNode {
+	zombies := []*ProcTreeNode{}
+	for _, n := range t.nodes {
+		if n.Zombie() {
+			zombies = append(zombies, n)
+		}
+	}
+	sortProcTreeNodes(zombies)
+	return zombies
+}
+
+// Orphan returns whether the parent of the process is not part of the tree.
+func (n *ProcTreeNode) Orphan() bool {
+	return n.Parent == nil && n.Stat.PPID != 0
+}
+
+// Zombie returns whether the process exited but was not yet waited for by
+// its parent.
+func (n *ProcTreeNode) Zombie() bool {
+	return n.Stat.State == "Z"
+}
+
+// Ancestors returns the ancestors of the process, starting with its parent.
+func (n *ProcTreeNode) Ancestors() []*ProcTreeNode {
+	ancestors := []*ProcTreeNode{}
+	for p := n.Parent; p != nil; p = p.Parent {
+		ancestors = append(ancestors, p)
+	}
+	return ancestors
+}
+
+// Descendants returns the descendants of the process in depth-first order,
+// with children ordered by PID.
+func (n *ProcTreeNode) Descendants() []*ProcTreeNode {
+	descendants := []*ProcTreeNode{}
+	for _, c := range n.Children {
+		descendants = append(descendants, c)
+		descendants = append(descendants, c.Descendants()...)
+	}
+	return descendants
+}
+
+// Usage returns the resource usage of the process and all its descendants.
+func (n *ProcTreeNode) Usage() ProcTreeUsage {
+	var u ProcTreeUsage
+	for _, p := range append([]*ProcTreeNode{n}, n.Descendants()...) {
+		u.Procs++
+		u.CPUTime += p.Stat.CPUTime()
+		u.ResidentMemory += p.Stat.ResidentMemory()
+		if p.IO != nil {
+			u.RChar += p.IO.RChar
+			u.WChar += p.IO.WChar
+			u.ReadBytes += p.IO.ReadBytes
+			u.WriteBytes += p.IO.WriteBytes
+		}
+	}
+	return u
+}
+
+// Children returns the PIDs of the child processes of all threads of the
+// process, read from /proc/[pid]/task/[tid]/children. This requires Linux
+// 3.5 built with CONFIG_PROC_CHILDREN, an error for which os.IsNotExist is
+// true is returned otherwise.
+func (p Proc) Children() ([]int, error) {
+	d, err := os.Open(p.path("task"))
+	if err != nil {
+		return nil, err
+	}
+	defer d.Close()
+
+	tids, err := d.Readdirnames(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	children := []int{}
+	for _, tid := range tids {
+		data, err := ioutil.ReadFile(p.path("task", tid, "children"))
+		if err != nil {
+			// Skip threads which exited since listing them.
+			if _, serr := os.Stat(p.path("task", tid)); os.IsNotExist(serr) {
+				continue
+			}
+			return nil, err
+		}
+
+		for _, f := range strings.Fields(string(data)) {
+			pid, err := strconv.Atoi(f)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, pid)
+		}
+	}
+	sort.Ints(children)
+
+	return children, nil
+}
+
+func sortProcTreeNodes(nodes []*ProcTreeNode) {
+	sort.Sort(procTreeNodesByPID(nodes))
+}
+
+// procTreeNodesByPID sorts process tree nodes by PID.
+type procTreeNodesByPID []*ProcTreeNode
+
+func (n procTreeNodesByPID) Len() int           { return len(n) }
+func (n procTreeNodesByPID) Swap(i, j int)      { n[i], n[j] = n[j], n[i] }
+func (n procTreeNodesByPID) Less(i, j int) bool { return n[i].PID < n[j].PID }
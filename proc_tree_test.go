@@ -0,0 +1,142 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"math"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestProcTree(t *testing.T) {
+	tree, err := FS("fixtures").NewProcTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 26233 has no stat file and is left out.
+	if want, have := 8, tree.Len(); want != have {
+		t.Errorf("want %d processes, have %d", want, have)
+	}
+	if tree.Get(26233) != nil {
+		t.Error("want process 26233 to be left out")
+	}
+
+	for _, tt := range []struct {
+		name string
+		want []int
+		have []*ProcTreeNode
+	}{
+		{name: "roots", want: []int{584, 26231, 26232, 26234, 26238}, have: tree.Roots()},
+		{name: "orphans", want: []int{584, 26231, 26232, 26234, 26238}, have: tree.Orphans()},
+		{name: "zombies", want: []int{26237}, have: tree.Zombies()},
+		{name: "children", want: []int{26235}, have: tree.Get(26234).Children},
+		{name: "descendants", want: []int{26235, 26236, 26237}, have: tree.Get(26234).Descendants()},
+		{name: "ancestors", want: []int{26236, 26235, 26234}, have: tree.Get(26237).Ancestors()},
+		{name: "no descendants", want: []int{}, have: tree.Get(26238).Descendants()},
+	} {
+		if have := procTreePIDs(tt.have); !reflect.DeepEqual(tt.want, have) {
+			t.Errorf("want %s %v, have %v", tt.name, tt.want, have)
+		}
+	}
+
+	if want, have := 26235, tree.Get(26236).Parent.PID; want != have {
+		t.Errorf("want parent %d, have %d", want, have)
+	}
+}
+
+func TestProcTreeUsage(t *testing.T) {
+	tree, err := FS("fixtures").NewProcTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u := tree.Get(26234).Usage()
+	if want, have := 5.7, u.CPUTime; math.Abs(want-have) > 1e-9 {
+		t.Errorf("want cpu time %f, have %f", want, have)
+	}
+	u.CPUTime = 0
+
+	want := ProcTreeUsage{
+		Procs:          4,
+		ResidentMemory: (1424 + 2000 + 1000) * 4096,
+		RChar:          15000,
+		WChar:          27000,
+		ReadBytes:      4096,
+		WriteBytes:     12288,
+	}
+	if want != u {
+		t.Errorf("want usage %+v, have %+v", want, u)
+	}
+}
+
+func TestProcChildren(t *testing.T) {
+	fs := FS("fixtures")
+
+	p, err := fs.NewProc(26235)
+	if err != nil {
+		t.Fatal(err)
+	}
+	children, err := p.Children()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{26236}; !reflect.DeepEqual(want, children) {
+		t.Errorf("want children %v, have %v", want, children)
+	}
+
+	// No children files.
+	p, err = fs.NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Children(); !os.IsNotExist(err) {
+		t.Errorf("want not exist error, have %v", err)
+	}
+}
+
+func TestResolveParent(t *testing.T) {
+	for _, tt := range []struct {
+		ppid   int
+		claims []int
+		want   int
+	}{
+		{ppid: 10, claims: nil, want: 10},
+		{ppid: 10, claims: []int{1}, want: 1},
+		{ppid: 10, claims: []int{30, 10, 20}, want: 10},
+		{ppid: 10, claims: []int{30, 20}, want: 20},
+	} {
+		if have := resolveParent(tt.ppid, tt.claims); tt.want != have {
+			t.Errorf("%d %v: want parent %d, have %d", tt.ppid, tt.claims, tt.want, have)
+		}
+	}
+}
+
+func TestCreatesCycle(t *testing.T) {
+	parents := map[int]int{1: 0, 2: 1, 3: 2, 4: 5, 5: 4, 6: 6}
+	for pid, want := range map[int]bool{1: false, 2: false, 3: false, 4: true, 5: true, 6: true} {
+		if have := createsCycle(parents, pid); want != have {
+			t.Errorf("%d: want cycle %t, have %t", pid, want, have)
+		}
+	}
+}
+
+func procTreePIDs(nodes []*ProcTreeNode) []int {
+	pids := make([]int, 0, len(nodes))
+	for _, n := range nodes {
+		pids = append(pids, n.PID)
+	}
+	return pids
+}
@@ -0,0 +1,159 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrPSINotSupported is returned when the kernel does not expose pressure
+// stall information, either because it is older than Linux 4.20 or because
+// PSI is disabled.
+var ErrPSINotSupported = errors.New("pressure stall information is not supported by the kernel")
+
+// PSILine is a single line of values as returned by /proc/pressure/*.
+// The Avg entries are averages over n seconds, as a percentage.
+// The Total is the total time tasks have been stalled.
+type PSILine struct {
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+	Total  time.Duration
+}
+
+// PSIStats represent pressure stall information from /proc/pressure/* or
+// the *.pressure files of a cgroup v2 directory, which look like this:
+//
+//	some avg10=0.10 avg60=2.00 avg300=3.85 total=15
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//
+// See Documentation/accounting/psi.txt in the kernel sources.
+// Some indicates the share of time in which at least some tasks are stalled.
+// Full indicates the share of time in which all non-idle tasks are stalled
+// simultaneously. Full is nil if the file has no "full" line, as is the case
+// for cpu on older kernels.
+type PSIStats struct {
+	Some *PSILine
+	Full *PSILine
+}
+
+// PSIStatsForResource reads pressure stall information for the specified
+// resource from /proc/pressure/<resource>. At time of writing this can be
+// either "cpu", "memory" or "io". ErrPSINotSupported is returned if
+// /proc/pressure does not exist.
+func (fs FS) PSIStatsForResource(resource string) (PSIStats, error) {
+	f, err := os.Open(fs.Path("pressure", resource))
+	if err != nil {
+		if os.IsNotExist(err) {
+			if _, serr := os.Stat(fs.Path("pressure")); os.IsNotExist(serr) {
+				return PSIStats{}, ErrPSINotSupported
+			}
+		}
+		return PSIStats{}, err
+	}
+	defer f.Close()
+
+	psi, err := ParsePSIStats(f)
+	if err != nil {
+		return PSIStats{}, fmt.Errorf("couldn't parse %s: %s", f.Name(), err)
+	}
+
+	return psi, nil
+}
+
+// ParsePSIStats parses pressure stall information in the format of the
+// files in /proc/pressure and the cgroup v2 *.pressure files.
+func ParsePSIStats(r io.Reader) (PSIStats, error) {
+	psiStats := PSIStats{}
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		line, err := parsePSILine(fields[1:])
+		if err != nil {
+			return PSIStats{}, err
+		}
+
+		switch fields[0] {
+		case "some":
+			psiStats.Some = line
+		case "full":
+			psiStats.Full = line
+		default:
+			return PSIStats{}, fmt.Errorf("unexpected PSI line %q", s.Text())
+		}
+	}
+
+	return psiStats, s.Err()
+}
+
+func parsePSILine(fields []string) (*PSILine, error) {
+	if len(fields) != 4 {
+		return nil, fmt.Errorf("unexpected number of PSI values: %d", len(fields))
+	}
+
+	var (
+		line PSILine
+		seen = map[string]bool{}
+	)
+	for _, field := range fields {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("malformed PSI value %q", field)
+		}
+
+		var (
+			avg *float64
+			err error
+		)
+		switch kv[0] {
+		case "avg10":
+			avg = &line.Avg10
+		case "avg60":
+			avg = &line.Avg60
+		case "avg300":
+			avg = &line.Avg300
+		case "total":
+			us, err := strconv.ParseUint(kv[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid PSI total %q: %s", kv[1], err)
+			}
+			line.Total = time.Duration(us) * time.Microsecond
+		default:
+			return nil, fmt.Errorf("unexpected PSI value %q", field)
+		}
+		if avg != nil {
+			if *avg, err = strconv.ParseFloat(kv[1], 64); err != nil {
+				return nil, fmt.Errorf("invalid PSI %s %q: %s", kv[0], kv[1], err)
+			}
+		}
+		seen[kv[0]] = true
+	}
+	if len(seen) != 4 {
+		return nil, fmt.Errorf("duplicate PSI values in %q", strings.Join(fields, " "))
+	}
+
+	return &line, nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPSIStatsForResource(t *testing.T) {
+	for _, tt := range []struct {
+		resource string
+		want     PSIStats
+	}{
+		{
+			resource: "cpu",
+			want: PSIStats{
+				Some: &PSILine{Avg10: 0.1, Avg60: 2, Avg300: 3.85, Total: 15 * time.Microsecond},
+			},
+		},
+		{
+			resource: "memory",
+			want: PSIStats{
+				Some: &PSILine{Avg10: 1.2, Avg60: 0.5, Avg300: 0.12, Total: 240617 * time.Microsecond},
+				Full: &PSILine{Avg10: 0.31, Avg60: 0.12, Avg300: 0.03, Total: 96451 * time.Microsecond},
+			},
+		},
+		{
+			resource: "io",
+			want:     PSIStats{Some: &PSILine{}, Full: &PSILine{}},
+		},
+	} {
+		have, err := FS("fixtures").PSIStatsForResource(tt.resource)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tt.want, have) {
+			t.Errorf("%s: want %+v, have %+v", tt.resource, tt.want, have)
+		}
+	}
+}
+
+func TestPSIStatsNotSupported(t *testing.T) {
+	_, err := FS("fixtures/buddyinfo/valid").PSIStatsForResource("cpu")
+	if want, have := ErrPSINotSupported, err; want != have {
+		t.Errorf("want error %v, have %v", want, have)
+	}
+
+	if _, err := FS("fixtures").PSIStatsForResource("irq"); err == nil || err == ErrPSINotSupported {
+		t.Errorf("want error for missing resource, have %v", err)
+	}
+}
+
+func TestParsePSIStatsInvalid(t *testing.T) {
+	for _, in := range []string{
+		"some avg10=0.10 avg60=2.00 avg300=3.85\n",
+		"some avg10=0.10 avg60=2.00 avg300=3.85 total=x\n",
+		"some avg10=0.10 avg60=2.00 avg300=x total=15\n",
+		"some avg10=0.10 avg60=2.00 avg300 total=15\n",
+		"some avg10=0.10 avg10=2.00 avg300=3.85 total=15\n",
+		"most avg10=0.10 avg60=2.00 avg300=3.85 total=15\n",
+	} {
+		if _, err := ParsePSIStats(strings.NewReader(in)); err == nil {
+			t.Errorf("want error parsing %q, have none", in)
+		}
+	}
+}
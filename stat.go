@@ -89,7 +89,7 @@ func NewStat() (Stat, error) {
 }
 
 // Parse a cpu statistics line and returns the CPUStat struct plus the cpu id (or -1 for the overall sum).
-func parseCPUStat(line string) (CPUStat, int64, error) {
+func parseCPUStat(line string, userHZ float64) (CPUStat, int64, error) {
 	cpuStat := CPUStat{}
 	var cpu string
 
@@ -159,7 +159,10 @@ func (fs FS) NewStat() (Stat, error) {
 	}
 	defer f.Close()
 
-	stat := Stat{}
+	var (
+		stat   = Stat{}
+		userHZ = float64(fs.UserHZ())
+	)
 
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
@@ -209,7 +212,7 @@ func (fs FS) NewStat() (Stat, error) {
 			stat.SoftIRQTotal = total
 			stat.SoftIRQ = softIRQStats
 		case strings.HasPrefix(parts[0], "cpu"):
-			cpuStat, cpuID, err := parseCPUStat(line)
+			cpuStat, cpuID, err := parseCPUStat(line, userHZ)
 			if err != nil {
 				return Stat{}, err
 			}
@@ -22,10 +22,10 @@ func TestStat(t *testing.T) {
 	}
 
 	// cpu
-	if want, have := float64(301854)/userHZ, s.CPUTotal.User; want != have {
+	if want, have := float64(301854)/100, s.CPUTotal.User; want != have {
 		t.Errorf("want cpu/user %v, have %v", want, have)
 	}
-	if want, have := float64(31)/userHZ, s.CPU[7].SoftIRQ; want != have {
+	if want, have := float64(31)/100, s.CPU[7].SoftIRQ; want != have {
 		t.Errorf("want cpu7/softirq %v, have %v", want, have)
 	}
 
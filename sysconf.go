@@ -0,0 +1,137 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sync"
+)
+
+// Originally, the USER_HZ value was dynamically retrieved via a sysconf call
+// which required cgo. However, that caused a lot of problems regarding
+// cross-compilation, so it was hardcoded to 100, which USER_HZ is on all
+// Go-supported platforms as of the time of this writing.
+//
+// The kernel passes USER_HZ and the page size to every process in its
+// auxiliary vector, which is what sysconf reads as well. They are read from
+// /proc/self/auxv of the FS, which also works for snapshots of /proc taken
+// on other systems, as long as they contain the auxv of the process which
+// /proc/self links to. The previous constants serve as defaults if it is not
+// available.
+//
+// See also the following discussions:
+//
+// - https://github.com/prometheus/node_exporter/issues/52
+// - https://github.com/prometheus/procfs/pull/2
+// - http://stackoverflow.com/questions/17410841/how-does-user-hz-solve-the-jiffy-scaling-issue
+const defaultUserHZ = 100
+
+// sysconf holds the system parameters of a FS.
+type sysconf struct {
+	userHZ   uint64
+	pageSize uint64
+}
+
+// The system parameters are process-wide state keyed by the cleaned mount
+// point, as FS is a plain mount point and cannot hold them itself. They are
+// detected once per mount point and kept for the lifetime of the process.
+var (
+	sysconfMtx sync.Mutex
+	// sysconfs holds the detected or overridden system parameters by
+	// cleaned mount point. Entries are never evicted.
+	sysconfs = map[string]*sysconf{}
+)
+
+// UserHZ returns the number of clock ticks per second, USER_HZ, which is the
+// unit of the CPU times in /proc/stat and /proc/[pid]/stat. It is read from
+// the AT_CLKTCK entry of /proc/self/auxv, unless set by SetUserHZ. If that is
+// not available, it defaults to 100.
+func (fs FS) UserHZ() uint64 {
+	sysconfMtx.Lock()
+	defer sysconfMtx.Unlock()
+
+	return fs.sysconf().userHZ
+}
+
+// PageSize returns the page size in bytes, which is the unit of the memory
+// sizes in /proc/[pid]/stat. It is read from the AT_PAGESZ entry of
+// /proc/self/auxv, unless set by SetPageSize. If that is not available, it
+// defaults to the page size of the running system.
+func (fs FS) PageSize() uint64 {
+	sysconfMtx.Lock()
+	defer sysconfMtx.Unlock()
+
+	return fs.sysconf().pageSize
+}
+
+// SetUserHZ overrides the USER_HZ value returned by UserHZ. It returns an
+// error if hz is zero. The override changes the value process-wide for the
+// mount point: it applies to every FS with the same mount point, in all
+// goroutines, until it is overridden again.
+func (fs FS) SetUserHZ(hz uint64) error {
+	if hz == 0 {
+		return fmt.Errorf("invalid USER_HZ %d", hz)
+	}
+
+	sysconfMtx.Lock()
+	defer sysconfMtx.Unlock()
+
+	fs.sysconf().userHZ = hz
+	return nil
+}
+
+// SetPageSize overrides the page size returned by PageSize. It returns an
+// error if size is zero. The override changes the value process-wide for the
+// mount point: it applies to every FS with the same mount point, in all
+// goroutines, until it is overridden again.
+func (fs FS) SetPageSize(size uint64) error {
+	if size == 0 {
+		return fmt.Errorf("invalid page size %d", size)
+	}
+
+	sysconfMtx.Lock()
+	defer sysconfMtx.Unlock()
+
+	fs.sysconf().pageSize = size
+	return nil
+}
+
+// sysconf returns the system parameters of the FS, reading them on first
+// use. sysconfMtx must be held.
+func (fs FS) sysconf() *sysconf {
+	key := path.Clean(string(fs))
+	if sc, ok := sysconfs[key]; ok {
+		return sc
+	}
+
+	sc := &sysconf{
+		userHZ:   defaultUserHZ,
+		pageSize: uint64(os.Getpagesize()),
+	}
+	if p, err := fs.Self(); err == nil {
+		if auxv, err := p.Auxv(); err == nil {
+			if hz := auxv[AuxvClkTck]; hz > 0 {
+				sc.userHZ = hz
+			}
+			if size := auxv[AuxvPageSz]; size > 0 {
+				sc.pageSize = size
+			}
+		}
+	}
+
+	sysconfs[key] = sc
+	return sc
+}
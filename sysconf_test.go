@@ -0,0 +1,108 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+func TestSysconf(t *testing.T) {
+	fs := FS("fixtures")
+	if want, have := uint64(100), fs.UserHZ(); want != have {
+		t.Errorf("want user hz %d, have %d", want, have)
+	}
+	if want, have := uint64(4096), fs.PageSize(); want != have {
+		t.Errorf("want page size %d, have %d", want, have)
+	}
+}
+
+func TestSysconfDefaults(t *testing.T) {
+	// No /proc/self/auxv.
+	fs := FS("fixtures/buddyinfo/valid")
+	if want, have := uint64(100), fs.UserHZ(); want != have {
+		t.Errorf("want user hz %d, have %d", want, have)
+	}
+	if want, have := uint64(os.Getpagesize()), fs.PageSize(); want != have {
+		t.Errorf("want page size %d, have %d", want, have)
+	}
+}
+
+func TestSysconfOverride(t *testing.T) {
+	fs := FS("fixtures/buddyinfo/short")
+	defer restoreSysconf(fs)()
+	if err := fs.SetUserHZ(250); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.SetPageSize(65536); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := uint64(250), FS("fixtures/buddyinfo/short/").UserHZ(); want != have {
+		t.Errorf("want user hz %d, have %d", want, have)
+	}
+
+	s := ProcStat{UTime: 1000, STime: 250, RSS: 10, fs: fs}
+	if want, have := 5.0, s.CPUTime(); want != have {
+		t.Errorf("want cpu time %f, have %f", want, have)
+	}
+	if want, have := 655360, s.ResidentMemory(); want != have {
+		t.Errorf("want resident memory %d, have %d", want, have)
+	}
+}
+
+func TestSysconfOverrideInvalid(t *testing.T) {
+	fs := FS("fixtures/buddyinfo/short")
+	defer restoreSysconf(fs)()
+
+	if err := fs.SetUserHZ(0); err == nil {
+		t.Error("want error setting user hz 0, have none")
+	}
+	if err := fs.SetPageSize(0); err == nil {
+		t.Error("want error setting page size 0, have none")
+	}
+
+	if want, have := uint64(100), fs.UserHZ(); want != have {
+		t.Errorf("want user hz %d, have %d", want, have)
+	}
+	if want, have := uint64(os.Getpagesize()), fs.PageSize(); want != have {
+		t.Errorf("want page size %d, have %d", want, have)
+	}
+}
+
+// restoreSysconf returns a function which restores the system parameters of
+// the FS to their state before the call.
+func restoreSysconf(fs FS) func() {
+	sysconfMtx.Lock()
+	defer sysconfMtx.Unlock()
+
+	key := path.Clean(string(fs))
+	prev, ok := sysconfs[key]
+	if ok {
+		saved := *prev
+		prev = &saved
+	}
+
+	return func() {
+		sysconfMtx.Lock()
+		defer sysconfMtx.Unlock()
+
+		if ok {
+			sysconfs[key] = prev
+		} else {
+			delete(sysconfs, key)
+		}
+	}
+}
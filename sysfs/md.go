@@ -0,0 +1,245 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// MDArray contains info from files in /sys/block/<md>/md for a single md
+// RAID array. Attributes which are not supported by the RAID level of the
+// array, e.g. degraded for raid0, are left unset.
+type MDArray struct {
+	// Name of the array, e.g. "md0". It matches the name in /proc/mdstat.
+	Name string
+	// State of the array, e.g. "clean", "active" or "read-auto".
+	ArrayState string
+	// RAID level, e.g. "raid1" or "raid0".
+	Level string
+	// Number of devices in a fully functional array.
+	RaidDisks int64
+	// Number of devices missing from the array.
+	Degraded *int64
+	// The running sync operation, e.g. "idle", "resync" or "check".
+	SyncAction string
+	// Number of sectors processed by the running sync operation, and the
+	// total number of sectors to process. Both are 0 if no operation is
+	// running.
+	SyncCompleted uint64
+	SyncTotal     uint64
+	// Number of sectors found to be inconsistent by the last check or
+	// repair operation.
+	MismatchCount *int64
+	// The member devices of the array, sorted by name.
+	Devices []MDArrayDevice
+}
+
+// MDArrayDevice contains info from files in /sys/block/<md>/md/dev-<device>
+// for a single member device of an md RAID array.
+type MDArrayDevice struct {
+	// Name of the device, e.g. "sda1".
+	Name string
+	// State flags of the device, e.g. "in_sync", "faulty", "spare" or
+	// "write_mostly".
+	State []string
+	// Number of read errors which were corrected and did not cause the
+	// device to be evicted from the array.
+	Errors int64
+	// The role of the device in the array, or nil for spare and faulty
+	// devices.
+	Slot *int64
+}
+
+// Faulty returns whether the device is marked as faulty.
+func (d MDArrayDevice) Faulty() bool {
+	for _, s := range d.State {
+		if s == "faulty" {
+			return true
+		}
+	}
+	return false
+}
+
+// MDArrays is a collection of info for every md RAID array in /sys/block. The
+// map keys are the array names, which match procfs.MDStat.Name.
+type MDArrays map[string]MDArray
+
+// NewMDArrays returns info for all md RAID arrays read from
+// /sys/block/<md>/md.
+func NewMDArrays() (MDArrays, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewMDArrays()
+}
+
+// NewMDArrays returns info for all md RAID arrays read from
+// /sys/block/<md>/md.
+func (fs FS) NewMDArrays() (MDArrays, error) {
+	matches, err := filepath.Glob(fs.Path("block/md*/md"))
+	if err != nil {
+		return nil, err
+	}
+
+	arrays := MDArrays{}
+	for _, m := range matches {
+		md, err := parseMDArray(m)
+		if err != nil {
+			return nil, err
+		}
+		md.Name = filepath.Base(filepath.Dir(m))
+		arrays[md.Name] = *md
+	}
+
+	return arrays, nil
+}
+
+// parseMDArray reads the attributes of the array from the md directory of
+// the block device.
+func parseMDArray(dir string) (*MDArray, error) {
+	md := &MDArray{Devices: []MDArrayDevice{}}
+
+	var err error
+	if md.ArrayState, err = readMDString(dir, "array_state"); err != nil {
+		return nil, err
+	}
+	if md.Level, err = readMDString(dir, "level"); err != nil {
+		return nil, err
+	}
+	if md.SyncAction, err = readMDString(dir, "sync_action"); err != nil {
+		return nil, err
+	}
+
+	raidDisks, err := readMDInt(dir, "raid_disks")
+	if err != nil {
+		return nil, err
+	}
+	if raidDisks != nil {
+		md.RaidDisks = *raidDisks
+	}
+	if md.Degraded, err = readMDInt(dir, "degraded"); err != nil {
+		return nil, err
+	}
+	if md.MismatchCount, err = readMDInt(dir, "mismatch_cnt"); err != nil {
+		return nil, err
+	}
+
+	// sync_completed is "none" if no operation is running, "delayed" if it
+	// waits for another array, and "<done> / <total>" otherwise.
+	completed, err := readMDString(dir, "sync_completed")
+	if err != nil {
+		return nil, err
+	}
+	if parts := strings.Split(completed, " / "); len(parts) == 2 {
+		if md.SyncCompleted, err = strconv.ParseUint(parts[0], 10, 64); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: %s", filepath.Join(dir, "sync_completed"), err)
+		}
+		if md.SyncTotal, err = strconv.ParseUint(parts[1], 10, 64); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: %s", filepath.Join(dir, "sync_completed"), err)
+		}
+	}
+
+	// The rd<slot> entries are symlinks to the dev-<device> directories, so
+	// only the latter are read.
+	devices, err := filepath.Glob(filepath.Join(dir, "dev-*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range devices {
+		dev, err := parseMDArrayDevice(d)
+		if err != nil {
+			return nil, err
+		}
+		md.Devices = append(md.Devices, *dev)
+	}
+
+	return md, nil
+}
+
+// parseMDArrayDevice reads the attributes of a member device from its
+// dev-<device> directory.
+func parseMDArrayDevice(dir string) (*MDArrayDevice, error) {
+	dev := &MDArrayDevice{
+		Name:  strings.TrimPrefix(filepath.Base(dir), "dev-"),
+		State: []string{},
+	}
+
+	state, err := readMDString(dir, "state")
+	if err != nil {
+		return nil, err
+	}
+	if state != "" {
+		dev.State = strings.Split(state, ",")
+	}
+
+	errors, err := readMDInt(dir, "errors")
+	if err != nil {
+		return nil, err
+	}
+	if errors != nil {
+		dev.Errors = *errors
+	}
+
+	// slot is "none" for devices which are not active in the array.
+	slot, err := readMDString(dir, "slot")
+	if err != nil {
+		return nil, err
+	}
+	if slot != "" && slot != "none" {
+		s, err := strconv.ParseInt(slot, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: %s", filepath.Join(dir, "slot"), err)
+		}
+		dev.Slot = &s
+	}
+
+	return dev, nil
+}
+
+// readMDString returns the trimmed contents of the attribute file, or the
+// empty string if the attribute does not exist.
+func readMDString(dir, name string) (string, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, name))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+// readMDInt returns the value of the attribute file, or nil if the attribute
+// does not exist.
+func readMDInt(dir, name string) (*int64, error) {
+	s, err := readMDString(dir, name)
+	if err != nil || s == "" {
+		return nil, err
+	}
+
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %s", filepath.Join(dir, name), err)
+	}
+
+	return &v, nil
+}
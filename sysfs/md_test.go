@@ -0,0 +1,94 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewMDArrays(t *testing.T) {
+	fs, err := NewFS("fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	arrays, err := fs.NewMDArrays()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		zero        int64
+		one         int64 = 1
+		two         int64 = 2
+		three       int64 = 3
+		mismatchCnt int64 = 16
+	)
+
+	want := MDArrays{
+		"md0": {
+			Name:          "md0",
+			ArrayState:    "clean",
+			Level:         "raid1",
+			RaidDisks:     2,
+			Degraded:      &one,
+			SyncAction:    "idle",
+			MismatchCount: &zero,
+			Devices: []MDArrayDevice{
+				{Name: "sdi1", State: []string{"in_sync"}, Slot: &zero},
+				{Name: "sdj1", State: []string{"faulty"}, Errors: 112},
+				{Name: "sdk", State: []string{"spare"}},
+			},
+		},
+		"md7": {
+			Name:          "md7",
+			ArrayState:    "active",
+			Level:         "raid6",
+			RaidDisks:     4,
+			Degraded:      &zero,
+			SyncAction:    "check",
+			SyncCompleted: 1627262960,
+			SyncTotal:     3906867712,
+			MismatchCount: &mismatchCnt,
+			Devices: []MDArrayDevice{
+				{Name: "sdb1", State: []string{"in_sync"}, Slot: &zero},
+				{Name: "sdc1", State: []string{"in_sync"}, Slot: &one},
+				{Name: "sdd1", State: []string{"in_sync"}, Slot: &two},
+				{Name: "sde1", State: []string{"in_sync", "write_mostly"}, Errors: 3, Slot: &three},
+			},
+		},
+		"md10": {
+			Name:       "md10",
+			ArrayState: "clean",
+			Level:      "raid0",
+			RaidDisks:  2,
+			Devices: []MDArrayDevice{
+				{Name: "sda3", State: []string{"in_sync"}},
+				{Name: "sdb3", State: []string{"in_sync"}},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(want, arrays) {
+		t.Errorf("want %+v, have %+v", want, arrays)
+	}
+
+	if !arrays["md0"].Devices[1].Faulty() {
+		t.Errorf("want device sdj1 to be faulty")
+	}
+	if arrays["md0"].Devices[0].Faulty() {
+		t.Errorf("want device sdi1 not to be faulty")
+	}
+}
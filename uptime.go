@@ -0,0 +1,59 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// Uptime represents the system uptime read from /proc/uptime.
+type Uptime struct {
+	// Time since boot in seconds, including time spent in suspend.
+	Uptime float64
+	// Sum of the time each CPU has spent idle in seconds.
+	Idle float64
+}
+
+// Uptime returns the system uptime read from /proc/uptime.
+func (fs FS) Uptime() (Uptime, error) {
+	data, err := ioutil.ReadFile(fs.Path("uptime"))
+	if err != nil {
+		return Uptime{}, err
+	}
+
+	return parseUptime(string(data))
+}
+
+func parseUptime(data string) (Uptime, error) {
+	fields := strings.Fields(data)
+	if len(fields) != 2 {
+		return Uptime{}, fmt.Errorf("unexpected uptime format: %q", data)
+	}
+
+	var (
+		u   Uptime
+		err error
+	)
+	if u.Uptime, err = strconv.ParseFloat(fields[0], 64); err != nil {
+		return Uptime{}, fmt.Errorf("couldn't parse uptime %q: %s", fields[0], err)
+	}
+	if u.Idle, err = strconv.ParseFloat(fields[1], 64); err != nil {
+		return Uptime{}, fmt.Errorf("couldn't parse idle time %q: %s", fields[1], err)
+	}
+
+	return u, nil
+}
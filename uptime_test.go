@@ -0,0 +1,32 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import "testing"
+
+func TestUptime(t *testing.T) {
+	u, err := FS("fixtures").Uptime()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Uptime{Uptime: 350735.47, Idle: 2763563.22}
+	if want != u {
+		t.Errorf("want %+v, have %+v", want, u)
+	}
+
+	if _, err := parseUptime("350735.47\n"); err == nil {
+		t.Error("want error parsing truncated uptime, have none")
+	}
+}
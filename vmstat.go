@@ -0,0 +1,174 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// VMStat holds the virtual memory statistics read from /proc/vmstat.
+//
+// The set of counters in /proc/vmstat changes between kernel releases, so
+// only the most commonly used ones are exposed as fields. A counter which is
+// not present in the file leaves its field at zero. All counters, including
+// those without a field, are available in Counters.
+type VMStat struct {
+	// Number of free pages.
+	NrFreePages uint64
+	// Number of pages waiting to be written back to disk.
+	NrDirty uint64
+	// Number of pages under writeback.
+	NrWriteback uint64
+	// Number of kilobytes paged in from disk.
+	PgpgIn uint64
+	// Number of kilobytes paged out to disk.
+	PgpgOut uint64
+	// Number of pages swapped in.
+	PswpIn uint64
+	// Number of pages swapped out.
+	PswpOut uint64
+	// Number of page faults.
+	PgFault uint64
+	// Number of major page faults, which required loading from disk.
+	PgMajFault uint64
+	// Number of pages scanned by kswapd.
+	PgScanKswapd uint64
+	// Number of pages scanned in direct reclaim.
+	PgScanDirect uint64
+	// Number of pages reclaimed by kswapd.
+	PgStealKswapd uint64
+	// Number of pages reclaimed in direct reclaim.
+	PgStealDirect uint64
+	// Number of processes killed by the OOM killer. Available since
+	// Linux 4.13.
+	OOMKill uint64
+	// Number of transparent huge pages allocated on page fault.
+	THPFaultAlloc uint64
+	// Number of transparent huge page allocations on page fault that fell
+	// back to regular pages.
+	THPFaultFallback uint64
+	// Number of transparent huge pages allocated by khugepaged.
+	THPCollapseAlloc uint64
+	// Number of transparent huge pages split into regular pages.
+	THPSplitPage uint64
+	// Number of times a process stalled to run memory compaction.
+	CompactStall uint64
+	// Number of compaction attempts which failed.
+	CompactFail uint64
+	// Number of compaction attempts which freed a page of the requested
+	// order.
+	CompactSuccess uint64
+
+	// Counters holds every counter in /proc/vmstat, keyed by its name.
+	Counters map[string]uint64
+}
+
+// NewVMStat returns the virtual memory statistics read from /proc/vmstat.
+func NewVMStat() (VMStat, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return VMStat{}, err
+	}
+
+	return fs.NewVMStat()
+}
+
+// NewVMStat returns the virtual memory statistics read from /proc/vmstat.
+func (fs FS) NewVMStat() (VMStat, error) {
+	f, err := os.Open(fs.Path("vmstat"))
+	if err != nil {
+		return VMStat{}, err
+	}
+	defer f.Close()
+
+	vs, err := parseVMStat(f)
+	if err != nil {
+		return VMStat{}, fmt.Errorf("couldn't parse %s: %s", f.Name(), err)
+	}
+
+	return vs, nil
+}
+
+func parseVMStat(r io.Reader) (VMStat, error) {
+	vs := VMStat{Counters: map[string]uint64{}}
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return VMStat{}, fmt.Errorf("malformed vmstat line: %q", s.Text())
+		}
+
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return VMStat{}, fmt.Errorf("invalid value for %s: %s", fields[0], err)
+		}
+		vs.Counters[fields[0]] = v
+
+		switch fields[0] {
+		case "nr_free_pages":
+			vs.NrFreePages = v
+		case "nr_dirty":
+			vs.NrDirty = v
+		case "nr_writeback":
+			vs.NrWriteback = v
+		case "pgpgin":
+			vs.PgpgIn = v
+		case "pgpgout":
+			vs.PgpgOut = v
+		case "pswpin":
+			vs.PswpIn = v
+		case "pswpout":
+			vs.PswpOut = v
+		case "pgfault":
+			vs.PgFault = v
+		case "pgmajfault":
+			vs.PgMajFault = v
+		case "pgscan_kswapd":
+			vs.PgScanKswapd = v
+		case "pgscan_direct":
+			vs.PgScanDirect = v
+		case "pgsteal_kswapd":
+			vs.PgStealKswapd = v
+		case "pgsteal_direct":
+			vs.PgStealDirect = v
+		case "oom_kill":
+			vs.OOMKill = v
+		case "thp_fault_alloc":
+			vs.THPFaultAlloc = v
+		case "thp_fault_fallback":
+			vs.THPFaultFallback = v
+		case "thp_collapse_alloc":
+			vs.THPCollapseAlloc = v
+		case "thp_split_page":
+			vs.THPSplitPage = v
+		case "compact_stall":
+			vs.CompactStall = v
+		case "compact_fail":
+			vs.CompactFail = v
+		case "compact_success":
+			vs.CompactSuccess = v
+		}
+	}
+
+	return vs, s.Err()
+}
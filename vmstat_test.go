@@ -0,0 +1,62 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVMStat(t *testing.T) {
+	vs, err := FS("fixtures").NewVMStat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name string
+		want uint64
+		have uint64
+	}{
+		{name: "nr_free_pages", want: 110081, have: vs.NrFreePages},
+		{name: "pgpgin", want: 501681692, have: vs.PgpgIn},
+		{name: "pswpout", want: 264, have: vs.PswpOut},
+		{name: "pgmajfault", want: 251063, have: vs.PgMajFault},
+		{name: "oom_kill", want: 3, have: vs.OOMKill},
+		{name: "thp_fault_fallback", want: 1202, have: vs.THPFaultFallback},
+		{name: "compact_stall", want: 17, have: vs.CompactStall},
+		{name: "pgalloc_normal", want: 3416396374, have: vs.Counters["pgalloc_normal"]},
+		{name: "balloon_inflate", want: 0, have: vs.Counters["balloon_inflate"]},
+	} {
+		if test.want != test.have {
+			t.Errorf("want %s %d, have %d", test.name, test.want, test.have)
+		}
+	}
+
+	if want, have := 25, len(vs.Counters); want != have {
+		t.Errorf("want %d counters, have %d", want, have)
+	}
+}
+
+func TestParseVMStatInvalid(t *testing.T) {
+	for _, in := range []string{
+		"nr_free_pages\n",
+		"nr_free_pages abc\n",
+		"nr_free_pages 1 2\n",
+	} {
+		if _, err := parseVMStat(strings.NewReader(in)); err == nil {
+			t.Errorf("want error parsing %q, have none", in)
+		}
+	}
+}